@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"gotask-backend/models"
 	"gotask-backend/modules/auth"
+	"gotask-backend/modules/notifications"
 	"gotask-backend/modules/organizations"
 	"gotask-backend/modules/projects"
 	"gotask-backend/modules/tasks"
@@ -37,13 +39,27 @@ func ConnectDatabase() {
 	}
 
 	database.AutoMigrate(&auth.User{},
-		&projects.Project{}, &tasks.Task{},
-		&tasks.Status{}, &tasks.Priority{}, &tasks.TaskUser{},
+		&projects.Project{}, &projects.ProjectMember{}, &projects.ProjectFavorite{}, &projects.ProjectView{},
+		&projects.ProjectTemplate{}, &projects.Milestone{},
+		&projects.Sprint{}, &projects.SprintTask{}, &projects.StatusSnapshot{}, &projects.SprintSnapshot{},
+		&tasks.Task{},
+		&tasks.Status{}, &tasks.Priority{}, &tasks.TaskUser{}, &tasks.TaskStatusTransition{}, &tasks.CustomField{},
+		&tasks.TaskDependency{}, &tasks.Comment{}, &tasks.CommentRevision{}, &tasks.Attachment{},
+		&tasks.ChecklistItem{}, &tasks.Label{}, &tasks.TaskLabel{}, &tasks.TaskWatcher{}, &tasks.TimeEntry{},
+		&notifications.Notification{},
 		&organizations.Organization{}, &organizations.OrganizationUser{})
 
 	DB = database
 
 	seedPriority()
+	backfillOrganizationAdmins()
+	backfillProjectKeys()
+	backfillTaskNumbers()
+	backfillStatusCategories()
+	backfillCompletedAt()
+	backfillStatusTransitions()
+	backfillTaskWatchers()
+	migrateSearchVectors()
 
 	fmt.Println("Database connected and seeded!")
 }
@@ -63,3 +79,120 @@ func seedPriority() {
 		}
 	}
 }
+
+// Owners of organizations created before roles existed become admins
+func backfillOrganizationAdmins() {
+	DB.Exec(`
+		UPDATE organization_users SET role = ?
+		FROM organizations
+		WHERE organizations.id = organization_users.organization_id
+		AND organizations.owner_id = organization_users.user_id
+		AND organization_users.role <> ?`, models.OrgRoleAdmin, models.OrgRoleAdmin)
+}
+
+// Projects created before keys existed get one derived from their name, trashed ones included
+func backfillProjectKeys() {
+	var pending []projects.Project
+	DB.Unscoped().Where("key = '' OR key IS NULL").Order("id asc").Find(&pending)
+
+	repo := projects.NewProjectRepository(DB)
+	for _, p := range pending {
+		key, err := projects.FreeKey(p.OrganizationID, projects.SuggestKey(p.Name), repo.KeyExists)
+		if err != nil {
+			log.Printf("backfill key of project %d failed: %v", p.ID, err)
+			continue
+		}
+		DB.Unscoped().Model(&projects.Project{}).Where("id = ?", p.ID).Update("key", key)
+	}
+}
+
+// Tasks created before per-project numbering get numbered in creation order
+func backfillTaskNumbers() {
+	DB.Exec(`
+		UPDATE tasks SET number = numbered.rn
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY created_at, id) AS rn
+			FROM tasks
+		) numbered
+		WHERE tasks.id = numbered.id AND tasks.number = 0
+		AND tasks.project_id IN (SELECT id FROM projects WHERE task_counter = 0)`)
+
+	DB.Exec(`
+		UPDATE projects SET task_counter = (SELECT COALESCE(MAX(number), 0) FROM tasks WHERE tasks.project_id = projects.id)
+		WHERE task_counter = 0`)
+}
+
+// Statuses created before categories existed are classified by their default names
+func backfillStatusCategories() {
+	DB.Model(&tasks.Status{}).Where("category = '' OR category IS NULL").Where("name IN ?", []string{"Done", "Cancel"}).
+		Update("category", tasks.StatusCategoryDone)
+	DB.Model(&tasks.Status{}).Where("category = '' OR category IS NULL").Where("name = ?", "On Progress").
+		Update("category", tasks.StatusCategoryInProgress)
+	DB.Model(&tasks.Status{}).Where("category = '' OR category IS NULL").
+		Update("category", tasks.StatusCategoryTodo)
+}
+
+// Tasks finished before completion times were tracked fall back to their creation time
+func backfillCompletedAt() {
+	DB.Exec(`
+		UPDATE tasks SET completed_at = tasks.created_at
+		FROM statuses
+		WHERE statuses.id = tasks.status_id AND statuses.category = ? AND tasks.completed_at IS NULL`,
+		tasks.StatusCategoryDone)
+}
+
+// Tasks created before the status history existed start it with their current status
+func backfillStatusTransitions() {
+	DB.Exec(`
+		INSERT INTO task_status_transitions (task_id, to_status_id, changed_by, changed_at)
+		SELECT tasks.id, tasks.status_id, 0, tasks.created_at FROM tasks
+		WHERE NOT EXISTS (SELECT 1 FROM task_status_transitions WHERE task_status_transitions.task_id = tasks.id)`)
+}
+
+// Tasks created before watchers existed are watched by their creator and assignees.
+// Only runs while nobody watches anything, so later unsubscriptions stick.
+func backfillTaskWatchers() {
+	DB.Exec(`
+		INSERT INTO task_watchers (task_id, user_id, muted, created_at)
+		SELECT task_id, user_id, false, NOW() FROM (
+			SELECT task_id, changed_by AS user_id FROM task_status_transitions
+			WHERE from_status_id IS NULL AND changed_by <> 0
+			UNION
+			SELECT task_id, user_id FROM task_users
+		) watchers
+		WHERE NOT EXISTS (SELECT 1 FROM task_watchers)
+		ON CONFLICT DO NOTHING`)
+}
+
+// Full-text search columns are generated by Postgres from the row itself, so they never
+// go stale and GORM only reads them. Titles and names weigh more than descriptions.
+func migrateSearchVectors() {
+	// Task descriptions joined the index later; a column generated from the title alone is rebuilt
+	DB.Exec(`
+		DO $$ BEGIN
+			IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'tasks'
+				AND column_name = 'search_vector' AND generation_expression NOT LIKE '%description%') THEN
+				ALTER TABLE tasks DROP COLUMN search_vector;
+			END IF;
+		END $$`)
+	DB.Exec(`
+		ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+			setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+		) STORED`)
+	DB.Exec(`CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector)`)
+
+	DB.Exec(`
+		ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+			setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+		) STORED`)
+	DB.Exec(`CREATE INDEX IF NOT EXISTS idx_projects_search_vector ON projects USING GIN (search_vector)`)
+
+	DB.Exec(`
+		ALTER TABLE comments ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(body, ''))) STORED`)
+	DB.Exec(`CREATE INDEX IF NOT EXISTS idx_comments_search_vector ON comments USING GIN (search_vector)`)
+}
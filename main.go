@@ -3,11 +3,18 @@ package main
 import (
 	"gotask-backend/config"
 	"gotask-backend/middlewares"
+	"gotask-backend/storage"
+	"gotask-backend/utils"
 	"log"
+	"os"
+	"strconv"
+	"time"
 
 	"gotask-backend/modules/auth"
+	"gotask-backend/modules/notifications"
 	"gotask-backend/modules/organizations"
 	"gotask-backend/modules/projects"
+	"gotask-backend/modules/search"
 	"gotask-backend/modules/tasks"
 
 	"github.com/gin-gonic/gin"
@@ -24,7 +31,8 @@ func main() {
 
 	// Apply Middleware (First thing!)
 	r.Use(middlewares.CORSMiddleware())
-	r.Use(middlewares.EnsureJSON())
+	// Uploads are multipart, every other body is JSON
+	r.Use(middlewares.EnsureJSON("/tasks/:id/attachments"))
 
 	// Dependency Injection for Auth
 	authRepo := auth.NewAuthRepository(config.DB)
@@ -36,19 +44,70 @@ func main() {
 	orgService := organizations.NewOrganizationService(orgRepo, authService)
 	orgHandler := organizations.NewOrganizationHandler(orgService)
 
+	// Dependency Injection for Notifications
+	notificationRepo := notifications.NewNotificationRepository(config.DB)
+	notificationService := notifications.NewNotificationService(notificationRepo)
+	notificationHandler := notifications.NewNotificationHandler(notificationService)
+
 	// Dependency Injection for Tasks
 	taskRepo := tasks.NewTaskRepository(config.DB)
-	taskService := tasks.NewTaskService(taskRepo, authService)
+	taskService := tasks.NewTaskService(taskRepo, authService, notificationService)
 	taskHandler := tasks.NewTaskHandler(taskService)
 
+	fileStorage, err := storage.FromEnv()
+	if err != nil {
+		log.Fatal("Error configuring file storage: ", err)
+	}
+	attachmentRepo := tasks.NewAttachmentRepository(config.DB)
+	attachmentService := tasks.NewAttachmentService(attachmentRepo, taskService, fileStorage, []byte(os.Getenv("SECRET_KEY")))
+	attachmentHandler := tasks.NewAttachmentHandler(attachmentService)
+
+	taskReportRepo := tasks.NewReportRepository(config.DB)
+	taskReportService := tasks.NewReportService(taskReportRepo, taskService)
+	taskReportHandler := tasks.NewReportHandler(taskReportService)
+
 	// Dependency Injection for Projects
 	projectRepo := projects.NewProjectRepository(config.DB)
 	projectService := projects.NewProjectService(projectRepo, taskService)
 	projectHandler := projects.NewProjectHandler(projectService)
 
+	milestoneRepo := projects.NewMilestoneRepository(config.DB)
+	milestoneService := projects.NewMilestoneService(milestoneRepo, projectService)
+	milestoneHandler := projects.NewMilestoneHandler(milestoneService)
+
+	sprintRepo := projects.NewSprintRepository(config.DB)
+	sprintService := projects.NewSprintService(sprintRepo, projectService)
+	sprintHandler := projects.NewSprintHandler(sprintService)
+
+	reportRepo := projects.NewReportRepository(config.DB)
+	reportService := projects.NewReportService(reportRepo, projectService)
+	reportHandler := projects.NewReportHandler(reportService)
+
+	// Dependency Injection for Search
+	searchRepo := search.NewSearchRepository(config.DB)
+	searchService := search.NewSearchService(searchRepo)
+	searchHandler := search.NewSearchHandler(searchService)
+
+	// Background job: empty the trash (TRASH_RETENTION_DAYS, default 30)
+	retentionDays, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || retentionDays <= 0 {
+		retentionDays = 30
+	}
+	utils.Every(time.Hour, "trash-purge", func() error {
+		if _, err := projectService.PurgeTrash(time.Duration(retentionDays) * 24 * time.Hour); err != nil {
+			return err
+		}
+		// Files of purged tasks go once their rows are gone
+		return attachmentService.PurgeOrphans()
+	})
+
+	// Background job: daily per-status and sprint snapshots for the CFD and burndown reports
+	utils.Every(time.Hour, "report-snapshots", reportService.RecordSnapshots)
+
 	// PUBLIC ROUTES
 	r.POST("/signup", authHandler.Signup)
 	r.POST("/login", authHandler.Login)
+	r.GET("/attachments/:id/download", attachmentHandler.Download) // signed URL, see tasks.AttachmentLinkTTL
 
 	// PROTECTED ROUTES
 	protected := r.Group("/")
@@ -56,21 +115,125 @@ func main() {
 	{
 		protected.GET("/projects", projectHandler.FindProjects)
 		protected.POST("/projects", projectHandler.CreateProject)
+		protected.GET("/projects/:id", projectHandler.GetProject)
+		protected.PATCH("/projects/:id", projectHandler.UpdateProject)
 		protected.DELETE("/projects/:id", projectHandler.DeleteProject)
+		protected.POST("/projects/:id/archive", projectHandler.ArchiveProject)
+		protected.POST("/projects/:id/unarchive", projectHandler.UnarchiveProject)
+		protected.POST("/projects/:id/favorite", projectHandler.FavoriteProject)
+		protected.DELETE("/projects/:id/favorite", projectHandler.UnfavoriteProject)
+		protected.GET("/projects/:id/members", projectHandler.GetMembers)
+		protected.POST("/projects/:id/members", projectHandler.AddMember)
+		protected.PATCH("/projects/:id/members/:userId", projectHandler.UpdateMemberRole)
+		protected.DELETE("/projects/:id/members/:userId", projectHandler.RemoveMember)
+		protected.POST("/projects/:id/clone", projectHandler.CloneProject)
+		protected.POST("/projects/:id/template", projectHandler.SaveAsTemplate)
+		protected.POST("/projects/:id/move", projectHandler.MoveProject)
+		protected.POST("/projects/:id/restore", projectHandler.RestoreProject)
+		protected.GET("/trash", projectHandler.GetTrash)
+
+		protected.GET("/projects/:id/milestones", milestoneHandler.FindMilestones)
+		protected.POST("/projects/:id/milestones", milestoneHandler.CreateMilestone)
+		protected.GET("/milestones/:id", milestoneHandler.GetMilestone)
+		protected.PATCH("/milestones/:id", milestoneHandler.UpdateMilestone)
+		protected.DELETE("/milestones/:id", milestoneHandler.DeleteMilestone)
+
+		protected.GET("/projects/:id/sprints", sprintHandler.FindSprints)
+		protected.POST("/projects/:id/sprints", sprintHandler.CreateSprint)
+		protected.GET("/sprints/:id", sprintHandler.GetSprint)
+		protected.PATCH("/sprints/:id", sprintHandler.UpdateSprint)
+		protected.DELETE("/sprints/:id", sprintHandler.DeleteSprint)
+		protected.POST("/sprints/:id/tasks", sprintHandler.AddTasks)
+		protected.DELETE("/sprints/:id/tasks/:taskId", sprintHandler.RemoveTask)
+		protected.POST("/sprints/:id/start", sprintHandler.StartSprint)
+		protected.POST("/sprints/:id/close", sprintHandler.CloseSprint)
+		protected.GET("/sprints/:id/report", sprintHandler.GetReport)
+
+		protected.GET("/projects/:id/stats", reportHandler.GetProjectStats)
+		protected.GET("/projects/:id/reports/cfd", reportHandler.GetCumulativeFlow)
+		protected.GET("/projects/:id/reports/burndown", reportHandler.GetBurndown)
+		protected.GET("/projects/:id/reports/cycle-time", taskReportHandler.GetFlowTime)
+		protected.GET("/projects/:id/reports/time", taskReportHandler.GetTimeTotals)
+		protected.GET("/timesheet", taskReportHandler.GetTimesheet)
+
+		protected.GET("/project-templates", projectHandler.FindTemplates)
+		protected.POST("/project-templates", projectHandler.CreateTemplate)
+		protected.GET("/project-templates/:id", projectHandler.GetTemplate)
+		protected.DELETE("/project-templates/:id", projectHandler.DeleteTemplate)
 
 		protected.GET("/projects/:id/tasks", taskHandler.FindTasksByProject)
 		protected.POST("/tasks", taskHandler.CreateTask)
+		protected.GET("/tasks/:id", taskHandler.GetTask)
 		protected.PATCH("/tasks/:id", taskHandler.UpdateTask)
 		protected.DELETE("/tasks/:id", taskHandler.DeleteTask)
+		protected.POST("/tasks/:id/restore", taskHandler.RestoreTask)
+		protected.GET("/tasks/:id/transitions", taskHandler.GetTransitions)
+		protected.GET("/tasks/:id/subtasks", taskHandler.GetSubtasks)
+		protected.GET("/tasks/:id/dependencies", taskHandler.GetDependencies)
+		protected.POST("/tasks/:id/dependencies", taskHandler.AddDependency)
+		protected.DELETE("/tasks/:id/dependencies/:otherId", taskHandler.RemoveDependency)
+		protected.GET("/projects/:id/dependencies", taskHandler.GetDependencyGraph)
+		protected.GET("/tasks/:id/comments", taskHandler.GetComments)
+		protected.POST("/tasks/:id/comments", taskHandler.CreateComment)
+		protected.PATCH("/comments/:id", taskHandler.UpdateComment)
+		protected.DELETE("/comments/:id", taskHandler.DeleteComment)
+		protected.GET("/comments/:id/history", taskHandler.GetCommentHistory)
+		protected.GET("/tasks/:id/checklist", taskHandler.GetChecklist)
+		protected.POST("/tasks/:id/checklist", taskHandler.AddChecklistItem)
+		protected.POST("/tasks/:id/checklist/bulk", taskHandler.AddChecklistItems)
+		protected.PUT("/tasks/:id/checklist/order", taskHandler.ReorderChecklist)
+		protected.PATCH("/tasks/:id/checklist", taskHandler.SetChecklistDone)
+		protected.DELETE("/tasks/:id/checklist", taskHandler.ClearChecklist)
+		protected.PATCH("/checklist-items/:id", taskHandler.UpdateChecklistItem)
+		protected.POST("/checklist-items/:id/toggle", taskHandler.ToggleChecklistItem)
+		protected.DELETE("/checklist-items/:id", taskHandler.DeleteChecklistItem)
+		protected.GET("/tasks/:id/watchers", taskHandler.GetWatchers)
+		protected.POST("/tasks/:id/watchers", taskHandler.AddWatchers)
+		protected.DELETE("/tasks/:id/watchers/:userId", taskHandler.RemoveWatcher)
+		protected.POST("/tasks/:id/watch", taskHandler.Watch)
+		protected.DELETE("/tasks/:id/watch", taskHandler.Unwatch)
+		protected.POST("/tasks/:id/mute", taskHandler.MuteTask)
+		protected.DELETE("/tasks/:id/mute", taskHandler.UnmuteTask)
+		protected.GET("/tasks/:id/time-entries", taskHandler.GetTimeEntries)
+		protected.POST("/tasks/:id/time-entries", taskHandler.LogTime)
+		protected.PATCH("/time-entries/:id", taskHandler.UpdateTimeEntry)
+		protected.DELETE("/time-entries/:id", taskHandler.DeleteTimeEntry)
+		protected.POST("/tasks/:id/timer", taskHandler.StartTimer)
+		protected.GET("/timer", taskHandler.GetTimer)
+		protected.POST("/timer/stop", taskHandler.StopTimer)
+		protected.GET("/tasks/:id/attachments", attachmentHandler.FindAttachments)
+		protected.POST("/tasks/:id/attachments", attachmentHandler.Upload)
+		protected.GET("/attachments/:id", attachmentHandler.GetAttachment)
+		protected.DELETE("/attachments/:id", attachmentHandler.DeleteAttachment)
+
+		protected.GET("/projects/:id/custom-fields", taskHandler.FindCustomFields)
+		protected.POST("/projects/:id/custom-fields", taskHandler.CreateCustomField)
+		protected.PATCH("/custom-fields/:id", taskHandler.UpdateCustomField)
+		protected.DELETE("/custom-fields/:id", taskHandler.DeleteCustomField)
+
+		protected.GET("/labels", taskHandler.FindLabels)
+		protected.POST("/labels", taskHandler.CreateLabel)
+		protected.GET("/projects/:id/labels", taskHandler.FindProjectLabels)
+		protected.POST("/projects/:id/labels", taskHandler.CreateProjectLabel)
+		protected.PATCH("/labels/:id", taskHandler.UpdateLabel)
+		protected.DELETE("/labels/:id", taskHandler.DeleteLabel)
+		protected.POST("/labels/:id/merge", taskHandler.MergeLabels)
 
 		protected.GET("/projects/:id/status", taskHandler.FindStatusesByProject)
 		protected.POST("/projects/:id/status", taskHandler.CreateStatus)
 		protected.PATCH("/status/:id", taskHandler.UpdateStatus)
 		protected.DELETE("/status/:id", taskHandler.DeleteStatus)
 
+		protected.GET("/search", searchHandler.Search)
+
+		protected.GET("/notifications", notificationHandler.FindNotifications)
+		protected.POST("/notifications/:id/read", notificationHandler.MarkRead)
+		protected.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+
 		protected.POST("/organizations", orgHandler.CreateOrganization)
 		protected.POST("/organizations/invite", orgHandler.InviteMember)
 		protected.GET("/organizations/members", orgHandler.GetMembers)
+		protected.PATCH("/organizations/members/:userId", orgHandler.UpdateMemberRole)
 	}
 
 	r.Run(":8080")
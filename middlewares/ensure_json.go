@@ -7,10 +7,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func EnsureJSON() gin.HandlerFunc {
+// EnsureJSON rejects request bodies that are not JSON. Routes listed in exempt
+// (as registered, e.g. "/tasks/:id/attachments") are left alone, for multipart uploads.
+func EnsureJSON(exempt ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 1. Check if the method is one that carries data
-		if c.Request.Method == "POST" || c.Request.Method == "PUT" || c.Request.Method == "PATCH" {
+		if (c.Request.Method == "POST" || c.Request.Method == "PUT" || c.Request.Method == "PATCH") && !isExempt(c.FullPath(), exempt) {
 
 			// 2. Check the Content-Type header
 			contentType := c.GetHeader("Content-Type")
@@ -29,3 +31,12 @@ func EnsureJSON() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+func isExempt(route string, exempt []string) bool {
+	for _, path := range exempt {
+		if route == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+package models
+
+// Organization roles: admins manage the organization and lead every project in it
+const (
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
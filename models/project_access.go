@@ -0,0 +1,61 @@
+package models
+
+import "gorm.io/gorm"
+
+// Project visibility: "org" projects are visible to every member of the organization,
+// "private" projects only to their explicit members
+const (
+	ProjectVisibilityOrg     = "org"
+	ProjectVisibilityPrivate = "private"
+)
+
+// Project roles, from most to least privileged
+const (
+	ProjectRoleLead        = "lead"
+	ProjectRoleContributor = "contributor"
+	ProjectRoleViewer      = "viewer"
+)
+
+var projectRoleRank = map[string]int{
+	ProjectRoleViewer:      1,
+	ProjectRoleContributor: 2,
+	ProjectRoleLead:        3,
+}
+
+// IsValidProjectRole checks a role coming from user input
+func IsValidProjectRole(role string) bool {
+	_, ok := projectRoleRank[role]
+	return ok
+}
+
+// HasProjectRole reports whether role is at least as privileged as minRole
+func HasProjectRole(role string, minRole string) bool {
+	return projectRoleRank[role] >= projectRoleRank[minRole]
+}
+
+// EffectiveProjectRole resolves what a user can do on a project.
+// Org admins are always leads, explicit membership wins next, and org-wide
+// projects fall back to contributor for every other org member.
+func EffectiveProjectRole(visibility string, memberRole string, isOrgAdmin bool) string {
+	if isOrgAdmin {
+		return ProjectRoleLead
+	}
+	if memberRole != "" {
+		return memberRole
+	}
+	if visibility == ProjectVisibilityOrg {
+		return ProjectRoleContributor
+	}
+	return ""
+}
+
+// VisibleToUser is a reusable filter for the 'projects' table that hides private
+// projects the user is not a member of
+func VisibleToUser(userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"projects.visibility = ? OR projects.id IN (SELECT project_id FROM project_members WHERE user_id = ?) OR projects.organization_id IN (SELECT organization_id FROM organization_users WHERE user_id = ? AND role = ?)",
+			ProjectVisibilityOrg, userID, userID, OrgRoleAdmin,
+		)
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+// NextTaskNumber bumps the project's task counter and returns the new value.
+// The row lock taken by the UPDATE serializes concurrent callers, so it must run
+// inside the same transaction that inserts the task.
+func NextTaskNumber(tx *gorm.DB, projectID uint) (uint, error) {
+	var number uint
+	err := tx.Raw("UPDATE projects SET task_counter = task_counter + 1 WHERE id = ? RETURNING task_counter", projectID).
+		Scan(&number).Error
+	return number, err
+}
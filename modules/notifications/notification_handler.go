@@ -0,0 +1,103 @@
+package notifications
+
+import (
+	"errors"
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service NotificationService
+}
+
+func NewNotificationHandler(service NotificationService) *Handler {
+	return &Handler{service: service}
+}
+
+// GET /notifications?unread=true&page=1&limit=20
+func (h *Handler) FindNotifications(c *gin.Context) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		utils.SendError(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		return
+	}
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, total, err := h.service.GetNotifications(orgID, user.ID, unreadOnly, page, limit)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch notifications")
+		return
+	}
+
+	utils.SendSuccess(c, "success", gin.H{
+		"notifications": notifications,
+		"meta": gin.H{
+			"current_page": page,
+			"limit":        limit,
+			"total_data":   total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// POST /notifications/:id/read
+func (h *Handler) MarkRead(c *gin.Context) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.MarkRead(c.Param("id"), orgID, user.ID); err != nil {
+		if errors.Is(err, ErrNotificationNotFound) {
+			utils.SendError(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to update notification")
+		return
+	}
+
+	utils.SendSuccess(c, "Notification marked as read")
+}
+
+// POST /notifications/read-all
+func (h *Handler) MarkAllRead(c *gin.Context) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	marked, err := h.service.MarkAllRead(orgID, user.ID)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to update notifications")
+		return
+	}
+
+	utils.SendSuccess(c, "Notifications marked as read", gin.H{"marked": marked})
+}
+
+// Helper: reads the org picked by RequireAuth from X-Organization-ID
+func requireOrgID(c *gin.Context) (string, bool) {
+	orgID, exists := c.Get("org_id")
+	if !exists {
+		utils.SendError(c, http.StatusBadRequest, "X-Organization-ID header is required")
+		return "", false
+	}
+	return orgID.(string), true
+}
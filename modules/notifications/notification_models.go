@@ -0,0 +1,24 @@
+package notifications
+
+import "time"
+
+// Notification types
+const (
+	TypeMention = "mention" // someone @mentioned the user
+	TypeReply   = "reply"   // someone replied to the user's comment
+	TypeComment = "comment" // someone commented on a task the user watches
+)
+
+// Notification tells a user about something that happened in one of their organizations
+type Notification struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	UserID         uint       `gorm:"index:idx_notifications_user_org,priority:1" json:"user_id"`
+	OrganizationID uint       `gorm:"index:idx_notifications_user_org,priority:2" json:"organization_id"`
+	Type           string     `json:"type"`
+	ActorID        uint       `json:"actor_id"` // who caused it
+	TaskID         *uint      `gorm:"index" json:"task_id"`
+	CommentID      *uint      `json:"comment_id"`
+	Message        string     `json:"message"`
+	ReadAt         *time.Time `json:"read_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,68 @@
+package notifications
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	CreateMany(notifications []Notification) error
+	FindByUser(orgID string, userID uint, unreadOnly bool, page int, limit int) ([]Notification, int64, error)
+	MarkRead(id string, orgID string, userID uint) (int64, error)
+	MarkAllRead(orgID string, userID uint) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &repository{db}
+}
+
+func (r *repository) CreateMany(notifications []Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Create(&notifications).Error
+}
+
+// FindByUser lists the user's notifications in the org, newest first
+func (r *repository) FindByUser(orgID string, userID uint, unreadOnly bool, page int, limit int) ([]Notification, int64, error) {
+	var notifications []Notification
+	var total int64
+
+	filtered := func() *gorm.DB {
+		query := r.db.Model(&Notification{}).Where("user_id = ? AND organization_id = ?", userID, orgID)
+		if unreadOnly {
+			query = query.Where("read_at IS NULL")
+		}
+		return query
+	}
+
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := filtered().
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+// MarkRead only touches the user's own notifications; 0 rows means there was none to mark
+func (r *repository) MarkRead(id string, orgID string, userID uint) (int64, error) {
+	result := r.db.Model(&Notification{}).
+		Where("id = ? AND user_id = ? AND organization_id = ?", id, userID, orgID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	return result.RowsAffected, result.Error
+}
+
+func (r *repository) MarkAllRead(orgID string, userID uint) (int64, error) {
+	result := r.db.Model(&Notification{}).
+		Where("user_id = ? AND organization_id = ? AND read_at IS NULL", userID, orgID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,76 @@
+package notifications
+
+import (
+	"errors"
+	"strconv"
+)
+
+type NotificationService interface {
+	Notify(orgID string, recipientIDs []uint, notification Notification) error
+	GetNotifications(orgID string, userID uint, unreadOnly bool, page int, limit int) ([]Notification, int64, error)
+	MarkRead(id string, orgID string, userID uint) error
+	MarkAllRead(orgID string, userID uint) (int64, error)
+}
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type notificationService struct {
+	repo NotificationRepository
+}
+
+func NewNotificationService(repo NotificationRepository) NotificationService {
+	return &notificationService{repo}
+}
+
+// Notify sends a copy of the notification to each recipient; the actor never
+// notifies themselves and duplicates are dropped
+func (s *notificationService) Notify(orgID string, recipientIDs []uint, notification Notification) error {
+	org, err := strconv.ParseUint(orgID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	var batch []Notification
+	seen := map[uint]bool{notification.ActorID: true}
+	for _, id := range recipientIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		n := notification
+		n.UserID = id
+		n.OrganizationID = uint(org)
+		batch = append(batch, n)
+	}
+	return s.repo.CreateMany(batch)
+}
+
+func (s *notificationService) GetNotifications(orgID string, userID uint, unreadOnly bool, page int, limit int) ([]Notification, int64, error) {
+	notifications, total, err := s.repo.FindByUser(orgID, userID, unreadOnly, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if notifications == nil {
+		notifications = []Notification{}
+	}
+	return notifications, total, nil
+}
+
+func (s *notificationService) MarkRead(id string, orgID string, userID uint) error {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return ErrNotificationNotFound
+	}
+	marked, err := s.repo.MarkRead(id, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if marked == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(orgID string, userID uint) (int64, error) {
+	return s.repo.MarkAllRead(orgID, userID)
+}
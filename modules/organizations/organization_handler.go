@@ -98,3 +98,37 @@ func (h *Handler) GetMembers(c *gin.Context) {
 
 	utils.SendSuccess(c, "Success", users)
 }
+
+// PATCH /organizations/members/:userId
+func (h *Handler) UpdateMemberRole(c *gin.Context) {
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+
+	orgIDInterface, exists := c.Get("org_id")
+	if !exists {
+		utils.SendError(c, http.StatusBadRequest, "X-Organization-ID header is required")
+		return
+	}
+	orgID, _ := strconv.ParseUint(orgIDInterface.(string), 10, 64)
+
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.UpdateMemberRole(uint(orgID), user.ID, uint(userID), req.Role); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Member role updated successfully")
+}
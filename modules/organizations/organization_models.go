@@ -12,7 +12,8 @@ type Organization struct {
 }
 
 type OrganizationUser struct {
-	OrganizationID uint `gorm:"primaryKey"`
-	UserID         uint `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"primaryKey"`
+	UserID         uint   `gorm:"primaryKey"`
+	Role           string `gorm:"default:member"`
 	CreatedAt      time.Time
 }
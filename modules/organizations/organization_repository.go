@@ -1,14 +1,18 @@
 package organizations
 
 import (
+	"gotask-backend/models"
+
 	"gorm.io/gorm"
 )
 
 type OrganizationRepository interface {
 	Create(org *Organization) error
 	FindByID(id uint) (*Organization, error)
-	AddMember(orgID uint, userID uint) error
+	AddMember(orgID uint, userID uint, role string) error
 	IsMember(userID uint, orgID uint) (bool, error)
+	IsAdmin(userID uint, orgID uint) (bool, error)
+	UpdateMemberRole(orgID uint, userID uint, role string) error
 	FindMemberIDs(orgID uint) ([]uint, error)
 }
 
@@ -30,10 +34,11 @@ func (r *organizationRepository) FindByID(id uint) (*Organization, error) {
 	return &org, err
 }
 
-func (r *organizationRepository) AddMember(orgID uint, userID uint) error {
+func (r *organizationRepository) AddMember(orgID uint, userID uint, role string) error {
 	return r.db.Table("organization_users").Create(map[string]interface{}{
 		"organization_id": orgID,
 		"user_id":         userID,
+		"role":            role,
 	}).Error
 }
 
@@ -45,6 +50,20 @@ func (r *organizationRepository) IsMember(userID uint, orgID uint) (bool, error)
 	return count > 0, err
 }
 
+func (r *organizationRepository) IsAdmin(userID uint, orgID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("organization_users").
+		Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *organizationRepository) UpdateMemberRole(orgID uint, userID uint, role string) error {
+	return r.db.Table("organization_users").
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Update("role", role).Error
+}
+
 func (r *organizationRepository) FindMemberIDs(orgID uint) ([]uint, error) {
 	var userIDs []uint
 	err := r.db.Table("organization_users").
@@ -2,6 +2,7 @@ package organizations
 
 import (
 	"errors"
+	"gotask-backend/models"
 	"gotask-backend/modules/auth"
 )
 
@@ -10,6 +11,7 @@ type OrganizationService interface {
 	CheckAccess(userID uint, orgID uint) (bool, error)
 	InviteMember(orgID uint, email string) error
 	GetMembers(orgID uint) ([]auth.User, error)
+	UpdateMemberRole(orgID uint, actorID uint, userID uint, role string) error
 }
 
 type organizationService struct {
@@ -36,8 +38,8 @@ func (s *organizationService) CreateOrganization(name string, ownerID uint) (*Or
 		return nil, err
 	}
 
-	// Tambahkan Owner sebagai Member (Manual Call)
-	if err := s.repo.AddMember(org.ID, ownerID); err != nil {
+	// Tambahkan Owner sebagai Admin (Manual Call)
+	if err := s.repo.AddMember(org.ID, ownerID, models.OrgRoleAdmin); err != nil {
 		return nil, err
 	}
 
@@ -64,7 +66,7 @@ func (s *organizationService) InviteMember(orgID uint, email string) error {
 		return errors.New("user is already a member")
 	}
 
-	return s.repo.AddMember(orgID, user.ID)
+	return s.repo.AddMember(orgID, user.ID, models.OrgRoleMember)
 }
 
 func (s *organizationService) GetMembers(orgID uint) ([]auth.User, error) {
@@ -81,3 +83,37 @@ func (s *organizationService) GetMembers(orgID uint) ([]auth.User, error) {
 	// Ambil Detail User dari Service Tetangga (Auth)
 	return s.authService.GetUsersByIDs(memberIDs)
 }
+
+func (s *organizationService) UpdateMemberRole(orgID uint, actorID uint, userID uint, role string) error {
+	if role != models.OrgRoleAdmin && role != models.OrgRoleMember {
+		return errors.New("role must be 'admin' or 'member'")
+	}
+
+	// Hanya admin yang boleh mengubah role
+	isAdmin, err := s.repo.IsAdmin(actorID, orgID)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return errors.New("only organization admins can change roles")
+	}
+
+	isMember, err := s.repo.IsMember(userID, orgID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("user is not a member of this organization")
+	}
+
+	// Owner selalu admin
+	org, err := s.repo.FindByID(orgID)
+	if err != nil {
+		return err
+	}
+	if org.OwnerID == userID && role != models.OrgRoleAdmin {
+		return errors.New("the organization owner must stay an admin")
+	}
+
+	return s.repo.UpdateMemberRole(orgID, userID, role)
+}
@@ -0,0 +1,136 @@
+package projects
+
+import (
+	"errors"
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MilestoneHandler struct {
+	service MilestoneService
+}
+
+func NewMilestoneHandler(service MilestoneService) *MilestoneHandler {
+	return &MilestoneHandler{service: service}
+}
+
+// GET /projects/:id/milestones
+func (h *MilestoneHandler) FindMilestones(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	milestones, err := h.service.GetMilestones(projectID, orgID, user.ID)
+	if err != nil {
+		sendMilestoneError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", milestones)
+}
+
+// GET /milestones/:id
+func (h *MilestoneHandler) GetMilestone(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	milestone, err := h.service.GetMilestone(id, orgID, user.ID)
+	if err != nil {
+		sendMilestoneError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", milestone)
+}
+
+// POST /projects/:id/milestones
+func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name        string     `json:"name" binding:"required"`
+		Description string     `json:"description"`
+		TargetDate  *time.Time `json:"target_date"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	milestone, err := h.service.CreateMilestone(projectID, orgID, user.ID, MilestoneInput{
+		Name:        req.Name,
+		Description: req.Description,
+		TargetDate:  req.TargetDate,
+	})
+	if err != nil {
+		sendMilestoneError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Milestone created successfully", milestone)
+}
+
+// PATCH /milestones/:id {"name", "description", "target_date", "clear_target_date", "state"}
+func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name            *string    `json:"name"`
+		Description     *string    `json:"description"`
+		TargetDate      *time.Time `json:"target_date"`
+		ClearTargetDate bool       `json:"clear_target_date"`
+		State           *string    `json:"state"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	milestone, err := h.service.UpdateMilestone(id, orgID, user.ID, UpdateMilestoneInput{
+		Name:            req.Name,
+		Description:     req.Description,
+		TargetDate:      req.TargetDate,
+		ClearTargetDate: req.ClearTargetDate,
+		State:           req.State,
+	})
+	if err != nil {
+		sendMilestoneError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Milestone updated successfully", milestone)
+}
+
+// DELETE /milestones/:id
+func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteMilestone(id, orgID, user.ID); err != nil {
+		sendMilestoneError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Milestone deleted successfully")
+}
+
+// Helper: same mapping as projects, plus a missing milestone is a 404
+func sendMilestoneError(c *gin.Context, err error) {
+	if errors.Is(err, ErrMilestoneNotFound) {
+		utils.SendError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	sendProjectError(c, err)
+}
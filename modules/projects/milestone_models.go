@@ -0,0 +1,37 @@
+package projects
+
+import "time"
+
+// Milestone states
+const (
+	MilestoneStateOpen   = "open"
+	MilestoneStateClosed = "closed"
+)
+
+type Milestone struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ProjectID   uint       `gorm:"index" json:"project_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	TargetDate  *time.Time `json:"target_date"`
+	State       string     `gorm:"default:open" json:"state"`
+	ClosedAt    *time.Time `json:"closed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// MilestoneStatusCount is one row of the per-status breakdown of a milestone
+type MilestoneStatusCount struct {
+	MilestoneID uint `json:"-"`
+	StatusTaskCount
+}
+
+// MilestoneProgress is a milestone with its task roll-up
+type MilestoneProgress struct {
+	Milestone
+	TotalTasks  int64             `json:"total_tasks"`
+	OpenTasks   int64             `json:"open_tasks"`
+	ClosedTasks int64             `json:"closed_tasks"`
+	Percentage  float64           `json:"percentage"`
+	Overdue     bool              `json:"overdue"`
+	ByStatus    []StatusTaskCount `json:"by_status"`
+}
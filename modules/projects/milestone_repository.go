@@ -0,0 +1,77 @@
+package projects
+
+import (
+	"gotask-backend/modules/tasks"
+
+	"gorm.io/gorm"
+)
+
+type MilestoneRepository interface {
+	Create(milestone *Milestone) error
+	FindByProject(projectID uint) ([]Milestone, error)
+	FindByID(id string) (*Milestone, error)
+	Update(milestone *Milestone, updates map[string]interface{}) error
+	Delete(milestone *Milestone) error
+	CountTasksPerStatus(milestoneIDs []uint) ([]MilestoneStatusCount, error)
+}
+
+type milestoneRepository struct {
+	db *gorm.DB
+}
+
+func NewMilestoneRepository(db *gorm.DB) MilestoneRepository {
+	return &milestoneRepository{db}
+}
+
+func (r *milestoneRepository) Create(milestone *Milestone) error {
+	return r.db.Create(milestone).Error
+}
+
+func (r *milestoneRepository) FindByProject(projectID uint) ([]Milestone, error) {
+	var milestones []Milestone
+	err := r.db.Where("project_id = ?", projectID).
+		Order("target_date asc NULLS LAST, id asc").
+		Find(&milestones).Error
+	return milestones, err
+}
+
+func (r *milestoneRepository) FindByID(id string) (*Milestone, error) {
+	var milestone Milestone
+	err := r.db.Where("id = ?", id).First(&milestone).Error
+	if err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (r *milestoneRepository) Update(milestone *Milestone, updates map[string]interface{}) error {
+	return r.db.Model(milestone).Updates(updates).Error
+}
+
+// Delete detaches the milestone's tasks (trashed ones included) before removing it
+func (r *milestoneRepository) Delete(milestone *Milestone) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&tasks.Task{}).
+			Where("milestone_id = ?", milestone.ID).
+			Update("milestone_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(milestone).Error
+	})
+}
+
+func (r *milestoneRepository) CountTasksPerStatus(milestoneIDs []uint) ([]MilestoneStatusCount, error) {
+	var counts []MilestoneStatusCount
+	if len(milestoneIDs) == 0 {
+		return counts, nil
+	}
+
+	err := r.db.Model(&tasks.Task{}).
+		Select("tasks.milestone_id, statuses.id AS status_id, statuses.name, statuses.index, statuses.category, COUNT(tasks.id) AS task_count").
+		Joins("JOIN statuses ON statuses.id = tasks.status_id").
+		Where("tasks.milestone_id IN ?", milestoneIDs).
+		Group("tasks.milestone_id, statuses.id, statuses.name, statuses.index, statuses.category").
+		Order("statuses.index asc").
+		Scan(&counts).Error
+	return counts, err
+}
@@ -0,0 +1,206 @@
+package projects
+
+import (
+	"errors"
+	"gotask-backend/models"
+	"gotask-backend/modules/tasks"
+	"time"
+)
+
+type MilestoneService interface {
+	GetMilestones(projectID string, orgID string, userID uint) ([]MilestoneProgress, error)
+	GetMilestone(id string, orgID string, userID uint) (*MilestoneProgress, error)
+	CreateMilestone(projectID string, orgID string, userID uint, input MilestoneInput) (*Milestone, error)
+	UpdateMilestone(id string, orgID string, userID uint, input UpdateMilestoneInput) (*Milestone, error)
+	DeleteMilestone(id string, orgID string, userID uint) error
+}
+
+var (
+	ErrMilestoneNotFound = errors.New("milestone not found")
+	ErrInvalidState      = errors.New("state must be 'open' or 'closed'")
+)
+
+type milestoneService struct {
+	repo           MilestoneRepository
+	projectService ProjectService
+}
+
+func NewMilestoneService(repo MilestoneRepository, projectService ProjectService) MilestoneService {
+	return &milestoneService{repo, projectService}
+}
+
+// Input DTO
+type MilestoneInput struct {
+	Name        string
+	Description string
+	TargetDate  *time.Time
+}
+
+type UpdateMilestoneInput struct {
+	Name            *string
+	Description     *string
+	TargetDate      *time.Time
+	ClearTargetDate bool
+	State           *string
+}
+
+// Helper: loads a milestone and checks the caller's role on its project
+func (s *milestoneService) authorize(id string, orgID string, userID uint, minRole string) (*Milestone, error) {
+	milestone, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrMilestoneNotFound
+	}
+
+	_, _, err = s.projectService.AuthorizeProject(interfaceToString(milestone.ProjectID), orgID, userID, minRole)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil, ErrMilestoneNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+func (s *milestoneService) GetMilestones(projectID string, orgID string, userID uint) ([]MilestoneProgress, error) {
+	project, _, err := s.projectService.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	milestones, err := s.repo.FindByProject(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withProgress(milestones)
+}
+
+func (s *milestoneService) GetMilestone(id string, orgID string, userID uint) (*MilestoneProgress, error) {
+	milestone, err := s.authorize(id, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.withProgress([]Milestone{*milestone})
+	if err != nil {
+		return nil, err
+	}
+	return &progress[0], nil
+}
+
+func (s *milestoneService) CreateMilestone(projectID string, orgID string, userID uint, input MilestoneInput) (*Milestone, error) {
+	project, _, err := s.projectService.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+
+	milestone := Milestone{
+		ProjectID:   project.ID,
+		Name:        input.Name,
+		Description: input.Description,
+		TargetDate:  input.TargetDate,
+		State:       MilestoneStateOpen,
+	}
+
+	if err := s.repo.Create(&milestone); err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (s *milestoneService) UpdateMilestone(id string, orgID string, userID uint, input UpdateMilestoneInput) (*Milestone, error) {
+	milestone, err := s.authorize(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if input.Name != nil {
+		if *input.Name == "" {
+			return nil, errors.New("milestone name cannot be empty")
+		}
+		updates["name"] = *input.Name
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.ClearTargetDate {
+		updates["target_date"] = nil
+	} else if input.TargetDate != nil {
+		updates["target_date"] = *input.TargetDate
+	}
+	if input.State != nil && *input.State != milestone.State {
+		switch *input.State {
+		case MilestoneStateClosed:
+			updates["state"] = MilestoneStateClosed
+			updates["closed_at"] = time.Now()
+		case MilestoneStateOpen:
+			updates["state"] = MilestoneStateOpen
+			updates["closed_at"] = nil
+		default:
+			return nil, ErrInvalidState
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.Update(milestone, updates); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindByID(id)
+}
+
+func (s *milestoneService) DeleteMilestone(id string, orgID string, userID uint) error {
+	milestone, err := s.authorize(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(milestone)
+}
+
+// Helper: rolls task counts up onto each milestone; "done" category statuses count as closed
+func (s *milestoneService) withProgress(milestones []Milestone) ([]MilestoneProgress, error) {
+	ids := make([]uint, 0, len(milestones))
+	for _, m := range milestones {
+		ids = append(ids, m.ID)
+	}
+
+	counts, err := s.repo.CountTasksPerStatus(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byMilestone := make(map[uint][]StatusTaskCount)
+	for _, c := range counts {
+		byMilestone[c.MilestoneID] = append(byMilestone[c.MilestoneID], c.StatusTaskCount)
+	}
+
+	now := time.Now()
+	result := make([]MilestoneProgress, 0, len(milestones))
+	for _, m := range milestones {
+		progress := MilestoneProgress{
+			Milestone: m,
+			ByStatus:  byMilestone[m.ID],
+		}
+		if progress.ByStatus == nil {
+			progress.ByStatus = []StatusTaskCount{}
+		}
+
+		for _, c := range progress.ByStatus {
+			progress.TotalTasks += c.TaskCount
+			if c.Category == tasks.StatusCategoryDone {
+				progress.ClosedTasks += c.TaskCount
+			}
+		}
+		progress.OpenTasks = progress.TotalTasks - progress.ClosedTasks
+
+		if progress.TotalTasks > 0 {
+			progress.Percentage = float64(progress.ClosedTasks) * 100 / float64(progress.TotalTasks)
+		}
+		progress.Overdue = m.State == MilestoneStateOpen &&
+			m.TargetDate != nil && m.TargetDate.Before(now) &&
+			progress.OpenTasks > 0
+
+		result = append(result, progress)
+	}
+	return result, nil
+}
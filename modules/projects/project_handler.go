@@ -1,6 +1,7 @@
 package projects
 
 import (
+	"errors"
 	"gotask-backend/modules/auth"
 	"gotask-backend/utils"
 	"net/http"
@@ -21,8 +22,18 @@ func NewProjectHandler(service ProjectService) *ProjectHandler {
 func (h *ProjectHandler) FindProjects(c *gin.Context) {
 	// Get Org ID from Context (Header: X-Organization-ID)
 	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+	options := ProjectListOptions{
+		IncludeArchived: c.Query("include_archived") == "true",
+		FavoritesOnly:   c.Query("favorites") == "true",
+		Sort:            c.DefaultQuery("sort", ProjectSortCreated),
+	}
+	if options.Sort != ProjectSortCreated && options.Sort != ProjectSortName && options.Sort != ProjectSortRecent {
+		utils.SendError(c, http.StatusBadRequest, "sort must be 'created', 'name' or 'recent'")
+		return
+	}
 
-	projects, err := h.service.GetProjects(orgID)
+	projects, err := h.service.GetProjects(orgID, user.ID, options)
 	if err != nil {
 		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch projects")
 		return
@@ -37,8 +48,11 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	orgID64, _ := strconv.ParseUint(orgIDStr, 10, 64)
 
 	var jsonInput struct {
+		Key         string `json:"key"`
 		Name        string `json:"name" binding:"required"`
 		Description string `json:"description"`
+		Visibility  string `json:"visibility" binding:"omitempty,oneof=org private"`
+		TemplateID  uint   `json:"template_id"`
 	}
 
 	if err := c.ShouldBindJSON(&jsonInput); err != nil {
@@ -49,13 +63,24 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	user := c.MustGet("user").(auth.User)
 
 	input := CreateProjectInput{
+		Key:            jsonInput.Key,
 		Name:           jsonInput.Name,
 		Description:    jsonInput.Description,
+		Visibility:     jsonInput.Visibility,
 		OrganizationID: uint(orgID64),
+		TemplateID:     jsonInput.TemplateID,
 	}
 
 	project, err := h.service.CreateProject(input, user.ID)
 	if err != nil {
+		if errors.Is(err, ErrTemplateNotFound) {
+			utils.SendError(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, ErrInvalidKey) || errors.Is(err, ErrKeyTaken) {
+			utils.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		utils.SendError(c, http.StatusInternalServerError, "Failed to create project")
 		return
 	}
@@ -63,15 +88,439 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	utils.SendSuccess(c, "Project created successfully", project)
 }
 
+// GET /projects/:id
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	project, err := h.service.GetProject(id, orgID, user.ID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			utils.SendError(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch project")
+		return
+	}
+
+	utils.SendSuccess(c, "Success", project)
+}
+
+// PATCH /projects/:id
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Visibility  *string `json:"visibility"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	project, err := h.service.UpdateProject(id, orgID, user.ID, UpdateProjectInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+	})
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Project updated successfully", project)
+}
+
+// POST /projects/:id/archive
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	project, err := h.service.ArchiveProject(id, orgID, user.ID)
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Project archived successfully", project)
+}
+
+// POST /projects/:id/unarchive
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	project, err := h.service.UnarchiveProject(id, orgID, user.ID)
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Project unarchived successfully", project)
+}
+
 // DELETE /projects/:id
 func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 	id := c.Param("id")
 	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
 
-	if err := h.service.DeleteProject(id, orgID); err != nil {
-		utils.SendError(c, http.StatusNotFound, err.Error())
+	if err := h.service.DeleteProject(id, orgID, user.ID); err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Project moved to trash")
+}
+
+// POST /projects/:id/restore
+func (h *ProjectHandler) RestoreProject(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	project, err := h.service.RestoreProject(id, orgID, user.ID)
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Project restored successfully", project)
+}
+
+// GET /trash
+func (h *ProjectHandler) GetTrash(c *gin.Context) {
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	trash, err := h.service.GetTrash(orgID, user.ID)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch trash")
+		return
+	}
+
+	utils.SendSuccess(c, "Success", trash)
+}
+
+// GET /projects/:id/members
+func (h *ProjectHandler) GetMembers(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	members, err := h.service.GetMembers(id, orgID, user.ID)
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", members)
+}
+
+// POST /projects/:id/members
+func (h *ProjectHandler) AddMember(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		UserID uint   `json:"user_id" binding:"required"`
+		Role   string `json:"role" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := h.service.AddMember(id, orgID, user.ID, ProjectMemberInput{UserID: req.UserID, Role: req.Role})
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Member added successfully")
+}
+
+// PATCH /projects/:id/members/:userId
+func (h *ProjectHandler) UpdateMemberRole(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	memberID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.service.UpdateMemberRole(id, orgID, user.ID, ProjectMemberInput{UserID: uint(memberID), Role: req.Role})
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Member role updated successfully")
+}
+
+// DELETE /projects/:id/members/:userId
+func (h *ProjectHandler) RemoveMember(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	memberID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.service.RemoveMember(id, orgID, user.ID, uint(memberID)); err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Member removed successfully")
+}
+
+// POST /projects/:id/clone
+func (h *ProjectHandler) CloneProject(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name             string `json:"name" binding:"required"`
+		IncludeTasks     bool   `json:"include_tasks"`
+		IncludeAssignees bool   `json:"include_assignees"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	project, err := h.service.CloneProject(id, orgID, user.ID, CloneProjectInput{
+		Name:             req.Name,
+		IncludeTasks:     req.IncludeTasks,
+		IncludeAssignees: req.IncludeAssignees,
+	})
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Project cloned successfully", project)
+}
+
+// POST /projects/:id/template
+func (h *ProjectHandler) SaveAsTemplate(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name         string `json:"name" binding:"required"`
+		Description  string `json:"description"`
+		IncludeTasks bool   `json:"include_tasks"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	template, err := h.service.SaveAsTemplate(id, orgID, user.ID, SaveAsTemplateInput{
+		Name:         req.Name,
+		Description:  req.Description,
+		IncludeTasks: req.IncludeTasks,
+	})
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Template saved successfully", template)
+}
+
+// GET /project-templates
+func (h *ProjectHandler) FindTemplates(c *gin.Context) {
+	orgID := c.MustGet("org_id").(string)
+
+	templates, err := h.service.GetTemplates(orgID)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch templates")
 		return
 	}
 
-	utils.SendSuccess(c, "Project deleted successfully")
+	utils.SendSuccess(c, "Success", templates)
+}
+
+// GET /project-templates/:id
+func (h *ProjectHandler) GetTemplate(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+
+	template, err := h.service.GetTemplate(id, orgID)
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", template)
+}
+
+// POST /project-templates
+func (h *ProjectHandler) CreateTemplate(c *gin.Context) {
+	orgIDStr := c.MustGet("org_id").(string)
+	orgID64, _ := strconv.ParseUint(orgIDStr, 10, 64)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name         string                `json:"name" binding:"required"`
+		Description  string                `json:"description"`
+		Statuses     []TemplateStatus      `json:"statuses" binding:"required,dive"`
+		Labels       []TemplateLabel       `json:"labels" binding:"dive"`
+		CustomFields []TemplateCustomField `json:"custom_fields" binding:"dive"`
+		Tasks        []TemplateTask        `json:"tasks" binding:"dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	template, err := h.service.CreateTemplate(ProjectTemplate{
+		OrganizationID: uint(orgID64),
+		Name:           req.Name,
+		Description:    req.Description,
+		Statuses:       req.Statuses,
+		Labels:         req.Labels,
+		CustomFields:   req.CustomFields,
+		Tasks:          req.Tasks,
+	}, user.ID)
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Template created successfully", template)
+}
+
+// DELETE /project-templates/:id
+func (h *ProjectHandler) DeleteTemplate(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteTemplate(id, orgID, user.ID); err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Template deleted successfully")
+}
+
+// POST /projects/:id/move
+func (h *ProjectHandler) MoveProject(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		OrganizationID uint   `json:"organization_id" binding:"required"`
+		AssigneeAction string `json:"assignee_action"`
+		DryRun         bool   `json:"dry_run"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.service.MoveProject(id, orgID, user.ID, MoveProjectInput{
+		DestinationOrgID: req.OrganizationID,
+		AssigneeAction:   req.AssigneeAction,
+		DryRun:           req.DryRun,
+	})
+	if err != nil {
+		if errors.Is(err, ErrMoveConflicts) {
+			// Send the report along so the client can offer unassign/invite
+			c.JSON(http.StatusConflict, utils.APIResponse{
+				Success: false,
+				Message: err.Error(),
+				Data:    result,
+			})
+			return
+		}
+		if errors.Is(err, ErrOrgAdminRequired) {
+			utils.SendError(c, http.StatusForbidden, err.Error())
+			return
+		}
+		sendProjectError(c, err)
+		return
+	}
+
+	if result.DryRun {
+		utils.SendSuccess(c, "Move preview", result)
+		return
+	}
+	utils.SendSuccess(c, "Project moved successfully", result)
+}
+
+// POST /projects/:id/favorite
+func (h *ProjectHandler) FavoriteProject(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.FavoriteProject(id, orgID, user.ID); err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Project added to favorites")
+}
+
+// DELETE /projects/:id/favorite
+func (h *ProjectHandler) UnfavoriteProject(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.UnfavoriteProject(id, orgID, user.ID); err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Project removed from favorites")
+}
+
+// Helper: not found -> 404, missing role -> 403, everything else is a bad request
+func sendProjectError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrProjectNotFound), errors.Is(err, ErrTemplateNotFound):
+		utils.SendError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrProjectForbidden):
+		utils.SendError(c, http.StatusForbidden, err.Error())
+	default:
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+	}
 }
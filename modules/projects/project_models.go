@@ -1,11 +1,147 @@
 package projects
 
-import "time"
+import (
+	"gotask-backend/modules/tasks"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Project struct {
-	ID             uint      `gorm:"primaryKey" json:"id"`
-	Name           string    `json:"name"`
-	Description    string    `json:"description"`
-	OrganizationID uint      `json:"organization_id"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Key            string         `gorm:"size:10;uniqueIndex:idx_projects_org_key,priority:2,where:key <> ''" json:"key"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description"`
+	OrganizationID uint           `gorm:"uniqueIndex:idx_projects_org_key,priority:1" json:"organization_id"`
+	Visibility     string         `gorm:"default:org" json:"visibility"`
+	TaskCounter    uint           `gorm:"default:0" json:"-"`
+	ArchivedAt     *time.Time     `json:"archived_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+type ProjectMember struct {
+	ProjectID uint   `gorm:"primaryKey" json:"project_id"`
+	UserID    uint   `gorm:"primaryKey" json:"user_id"`
+	Role      string `json:"role"`
+	CreatedAt time.Time
+}
+
+// ProjectFavorite is a project starred by a user
+type ProjectFavorite struct {
+	UserID    uint `gorm:"primaryKey"`
+	ProjectID uint `gorm:"primaryKey;index"`
+	CreatedAt time.Time
+}
+
+// ProjectView remembers when a user last opened a project
+type ProjectView struct {
+	UserID    uint      `gorm:"primaryKey"`
+	ProjectID uint      `gorm:"primaryKey;index"`
+	ViewedAt  time.Time `gorm:"index"`
+}
+
+// ProjectListItem is a project as listed by GET /projects, with the caller's favorite and last visit
+type ProjectListItem struct {
+	Project
+	IsFavorite   bool       `json:"is_favorite"`
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+}
+
+// ProjectMemberDetail is a project member joined with the user's email
+type ProjectMemberDetail struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StatusTaskCount is the number of tasks currently sitting in a status
+type StatusTaskCount struct {
+	StatusID  uint   `json:"status_id"`
+	Name      string `json:"name"`
+	Index     int    `json:"index"`
+	Category  string `json:"category"`
+	TaskCount int64  `json:"task_count"`
+}
+
+// ProjectDetail is the response for GET /projects/:id
+type ProjectDetail struct {
+	Project
+	Role        string            `json:"role"`
+	StatusCount int64             `json:"status_count"`
+	TaskCount   int64             `json:"task_count"`
+	Statuses    []StatusTaskCount `json:"statuses"`
+}
+
+// MoveConflict is a user tied to a project who is not in the destination organization
+type MoveConflict struct {
+	UserID            uint   `json:"user_id"`
+	Email             string `json:"email"`
+	AssignedTaskCount int64  `json:"assigned_task_count"`
+	ProjectRole       string `json:"project_role"`
+}
+
+// MoveProjectResult is the report returned by POST /projects/:id/move
+type MoveProjectResult struct {
+	Project        *Project       `json:"project"`
+	Conflicts      []MoveConflict `json:"conflicts"`
+	AssigneeAction string         `json:"assignee_action"`
+	KeyChanged     bool           `json:"key_changed"`
+	DryRun         bool           `json:"dry_run"`
+}
+
+// TrashedProject is a project waiting in the trash with the tasks deleted alongside it
+type TrashedProject struct {
+	Project
+	TaskCount int64 `json:"task_count"`
+}
+
+// Trash is the response for GET /trash
+type Trash struct {
+	Projects []TrashedProject `json:"projects"`
+	Tasks    []tasks.Task     `json:"tasks"`
+}
+
+// ProjectTemplate is a reusable blueprint for new projects in an organization
+type ProjectTemplate struct {
+	ID             uint                  `gorm:"primaryKey" json:"id"`
+	OrganizationID uint                  `json:"organization_id"`
+	Name           string                `json:"name"`
+	Description    string                `json:"description"`
+	Statuses       []TemplateStatus      `gorm:"serializer:json" json:"statuses"`
+	Labels         []TemplateLabel       `gorm:"serializer:json" json:"labels"`
+	CustomFields   []TemplateCustomField `gorm:"serializer:json" json:"custom_fields"`
+	Tasks          []TemplateTask        `gorm:"serializer:json" json:"tasks"`
+	CreatedBy      uint                  `json:"created_by"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+type TemplateStatus struct {
+	Name     string `json:"name" binding:"required"`
+	Category string `json:"category"`
+}
+
+type TemplateLabel struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+}
+
+type TemplateCustomField struct {
+	Name     string   `json:"name" binding:"required"`
+	Type     string   `json:"type" binding:"required"`
+	Options  []string `json:"options"`
+	Required bool     `json:"required"`
+}
+
+// TemplateTask is a starter task; its dates are day offsets from the project's creation
+type TemplateTask struct {
+	Title           string   `json:"title" binding:"required"`
+	Description     string   `json:"description"` // Markdown
+	StatusName      string   `json:"status_name"`
+	PriorityName    string   `json:"priority_name"`
+	Labels          []string `json:"labels"` // label names, the template's own first, then the organization's
+	StartOffsetDays *int     `json:"start_offset_days"`
+	EndOffsetDays   *int     `json:"end_offset_days"`
+	EstimateMinutes *int     `json:"estimate_minutes"`
 }
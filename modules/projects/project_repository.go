@@ -3,19 +3,61 @@ package projects
 import (
 	"gotask-backend/models"
 	"gotask-backend/modules/tasks"
+	"gotask-backend/utils"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProjectRepository interface {
-	FindAllByOrg(orgID string) ([]Project, error)
+	FindAllByOrg(orgID string, userID uint, options ProjectListOptions) ([]ProjectListItem, error)
 	FindByIDAndOrg(id string, orgID string) (*Project, error)
-	Create(project *Project) error
+	Create(project *Project, keyChosen bool, leadID uint, template *ProjectTemplate) error
+	AddFavorite(projectID uint, userID uint) error
+	RemoveFavorite(projectID uint, userID uint) error
+	RecordView(projectID uint, userID uint) error
+	Update(project *Project, updates map[string]interface{}) error
 	Delete(project *Project) error
+	KeyExists(orgID uint, key string) (bool, error)
 
-	// Task cleanup helpers
-	DeleteTasksByProject(projectID uint) error
-	ClearTaskAssignees(projectID uint) error
+	// Detail helpers
+	CountTasks(projectID uint) (int64, error)
+	CountTasksPerStatus(projectID uint) ([]StatusTaskCount, error)
+
+	// Membership
+	FindMemberRole(projectID uint, userID uint) (string, error)
+	FindMembers(projectID uint) ([]ProjectMemberDetail, error)
+	AddMember(member *ProjectMember) error
+	UpdateMemberRole(projectID uint, userID uint, role string) error
+	RemoveMember(projectID uint, userID uint) error
+	IsOrgMember(orgID uint, userID uint) (bool, error)
+	IsOrgAdmin(orgID uint, userID uint) (bool, error)
+
+	// Templates & cloning
+	CreateTemplate(template *ProjectTemplate) error
+	FindTemplatesByOrg(orgID string) ([]ProjectTemplate, error)
+	FindTemplateByIDAndOrg(id string, orgID string) (*ProjectTemplate, error)
+	DeleteTemplate(template *ProjectTemplate) error
+	FindStatuses(projectID uint) ([]tasks.Status, error)
+	FindCustomFields(projectID uint) ([]tasks.CustomField, error)
+	FindTasksWithDetails(projectID uint) ([]tasks.Task, error)
+	FindLabels(projectID uint) ([]tasks.Label, error)
+	FindTaskLabelNames(projectID uint) (map[uint][]string, error)
+	CloneProject(source *Project, target *Project, creatorID uint, options CloneProjectInput) error
+
+	// Moving between organizations
+	FindUsersOutsideOrg(projectID uint, orgID uint) ([]MoveConflict, error)
+	MoveProject(project *Project, destOrgID uint, key string, assigneeAction string, userIDs []uint) error
+
+	// Trash
+	TrashProject(project *Project) error
+	FindTrashedByIDAndOrg(id string, orgID string) (*Project, error)
+	RestoreProject(project *Project) error
+	FindTrashedProjects(orgID string, userID uint) ([]TrashedProject, error)
+	FindTrashedTasks(orgID string, userID uint) ([]tasks.Task, error)
+	PurgeTrash(cutoff time.Time) (int64, error)
 }
 
 type projectRepository struct {
@@ -26,15 +68,52 @@ func NewProjectRepository(db *gorm.DB) ProjectRepository {
 	return &projectRepository{db}
 }
 
-// Fetch all projects in the Organization
-func (r *projectRepository) FindAllByOrg(orgID string) ([]Project, error) {
-	var projects []Project
-	err := r.db.
-		Scopes(models.ByOrg(orgID)).
-		Find(&projects).Error
+// Fetch all projects in the Organization the user can see (archived ones only when asked),
+// flagged with the user's favorites and last visits
+func (r *projectRepository) FindAllByOrg(orgID string, userID uint, options ProjectListOptions) ([]ProjectListItem, error) {
+	var projects []ProjectListItem
+	query := r.db.Model(&Project{}).
+		Scopes(models.ByOrg(orgID), models.VisibleToUser(userID)).
+		Select("projects.*, project_favorites.user_id IS NOT NULL AS is_favorite, project_views.viewed_at AS last_viewed_at").
+		Joins("LEFT JOIN project_favorites ON project_favorites.project_id = projects.id AND project_favorites.user_id = ?", userID).
+		Joins("LEFT JOIN project_views ON project_views.project_id = projects.id AND project_views.user_id = ?", userID)
+	if !options.IncludeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+	if options.FavoritesOnly {
+		query = query.Where("project_favorites.user_id IS NOT NULL")
+	}
+
+	switch options.Sort {
+	case ProjectSortRecent:
+		query = query.Order("project_views.viewed_at DESC NULLS LAST").Order("projects.created_at asc")
+	case ProjectSortName:
+		query = query.Order("projects.name asc")
+	default:
+		query = query.Order("projects.created_at asc")
+	}
+
+	err := query.Order("projects.id asc").Scan(&projects).Error
 	return projects, err
 }
 
+func (r *projectRepository) AddFavorite(projectID uint, userID uint) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ProjectFavorite{ProjectID: projectID, UserID: userID}).Error
+}
+
+func (r *projectRepository) RemoveFavorite(projectID uint, userID uint) error {
+	return r.db.Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&ProjectFavorite{}).Error
+}
+
+// RecordView keeps only the latest visit per user and project
+func (r *projectRepository) RecordView(projectID uint, userID uint) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"viewed_at"}),
+	}).Create(&ProjectView{ProjectID: projectID, UserID: userID, ViewedAt: time.Now()}).Error
+}
+
 // Find a specific project
 func (r *projectRepository) FindByIDAndOrg(id string, orgID string) (*Project, error) {
 	var project Project
@@ -48,18 +127,910 @@ func (r *projectRepository) FindByIDAndOrg(id string, orgID string) (*Project, e
 	return &project, nil
 }
 
-func (r *projectRepository) Create(project *Project) error {
-	return r.db.Create(project).Error
+// Create saves a new project led by leadID together with its template's content, or the
+// default statuses without a template, in one transaction. keyChosen tells whether the
+// user picked project.Key or it was derived from the name (see claimKey).
+func (r *projectRepository) Create(project *Project, keyChosen bool, leadID uint, template *ProjectTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganizations(tx, project.OrganizationID); err != nil {
+			return err
+		}
+		key, err := claimKey(tx, project.OrganizationID, project.Key, keyChosen)
+		if err != nil {
+			return err
+		}
+		project.Key = key
+		if err := tx.Create(project).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&ProjectMember{ProjectID: project.ID, UserID: leadID, Role: models.ProjectRoleLead}).Error; err != nil {
+			return err
+		}
+		if template != nil {
+			return applyTemplate(tx, project.ID, template, project.CreatedAt, leadID)
+		}
+		statuses := tasks.DefaultStatuses(project.ID)
+		return tx.Create(&statuses).Error
+	})
+}
+
+func (r *projectRepository) Update(project *Project, updates map[string]interface{}) error {
+	return r.db.Model(project).Updates(updates).Error
 }
 
 func (r *projectRepository) Delete(project *Project) error {
 	return r.db.Delete(project).Error
 }
 
-func (r *projectRepository) ClearTaskAssignees(projectID uint) error {
-	return r.db.Exec("DELETE FROM task_users WHERE task_id IN (SELECT id FROM tasks WHERE project_id = ?)", projectID).Error
+// KeyExists also looks in the trash: a trashed project keeps its key until purged
+func (r *projectRepository) KeyExists(orgID uint, key string) (bool, error) {
+	return keyExists(r.db, orgID, key)
+}
+
+func keyExists(tx *gorm.DB, orgID uint, key string) (bool, error) {
+	var count int64
+	err := tx.Unscoped().Model(&Project{}).
+		Where("organization_id = ? AND key = ?", orgID, key).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Helper: locks organization rows in id order. Projects get their keys while their
+// organization is locked, so two projects entering an org at once cannot pick the same key.
+func lockOrganizations(tx *gorm.DB, orgIDs ...uint) error {
+	return tx.Exec("SELECT id FROM organizations WHERE id IN ? ORDER BY id FOR UPDATE", orgIDs).Error
+}
+
+// Helper: settles a project key once the org is locked. A key the user chose must be
+// free (ErrKeyTaken); a derived one moves on to the first free numeric suffix.
+func claimKey(tx *gorm.DB, orgID uint, key string, chosen bool) (string, error) {
+	if !chosen {
+		return FreeKey(orgID, key, func(orgID uint, key string) (bool, error) {
+			return keyExists(tx, orgID, key)
+		})
+	}
+	taken, err := keyExists(tx, orgID, key)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", ErrKeyTaken
+	}
+	return key, nil
+}
+
+func (r *projectRepository) FindMemberRole(projectID uint, userID uint) (string, error) {
+	var roles []string
+	err := r.db.Model(&ProjectMember{}).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Pluck("role", &roles).Error
+	if err != nil || len(roles) == 0 {
+		return "", err
+	}
+	return roles[0], nil
+}
+
+func (r *projectRepository) FindMembers(projectID uint) ([]ProjectMemberDetail, error) {
+	var members []ProjectMemberDetail
+	err := r.db.Model(&ProjectMember{}).
+		Select("project_members.user_id, users.email, project_members.role, project_members.created_at").
+		Joins("JOIN users ON users.id = project_members.user_id").
+		Where("project_members.project_id = ?", projectID).
+		Order("project_members.created_at asc").
+		Scan(&members).Error
+	return members, err
+}
+
+func (r *projectRepository) AddMember(member *ProjectMember) error {
+	return r.db.Create(member).Error
+}
+
+func (r *projectRepository) UpdateMemberRole(projectID uint, userID uint, role string) error {
+	return r.db.Model(&ProjectMember{}).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Update("role", role).Error
+}
+
+func (r *projectRepository) RemoveMember(projectID uint, userID uint) error {
+	return r.db.Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&ProjectMember{}).Error
+}
+
+func (r *projectRepository) IsOrgMember(orgID uint, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("organization_users").
+		Where("user_id = ? AND organization_id = ?", userID, orgID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *projectRepository) IsOrgAdmin(orgID uint, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("organization_users").
+		Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *projectRepository) CountTasks(projectID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&tasks.Task{}).Where("project_id = ?", projectID).Count(&count).Error
+	return count, err
+}
+
+func (r *projectRepository) CountTasksPerStatus(projectID uint) ([]StatusTaskCount, error) {
+	var counts []StatusTaskCount
+	err := r.db.Model(&tasks.Status{}).
+		Select("statuses.id AS status_id, statuses.name, statuses.index, statuses.category, COUNT(tasks.id) AS task_count").
+		Joins("LEFT JOIN tasks ON tasks.status_id = statuses.id AND tasks.project_id = statuses.project_id AND tasks.deleted_at IS NULL").
+		Where("statuses.project_id = ?", projectID).
+		Group("statuses.id, statuses.name, statuses.index, statuses.category").
+		Order("statuses.index asc").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *projectRepository) CreateTemplate(template *ProjectTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *projectRepository) FindTemplatesByOrg(orgID string) ([]ProjectTemplate, error) {
+	var templates []ProjectTemplate
+	err := r.db.Scopes(models.ByOrg(orgID)).Order("name asc").Find(&templates).Error
+	return templates, err
+}
+
+func (r *projectRepository) FindTemplateByIDAndOrg(id string, orgID string) (*ProjectTemplate, error) {
+	var template ProjectTemplate
+	err := r.db.
+		Where("id = ? AND organization_id = ?", id, orgID).
+		First(&template).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *projectRepository) DeleteTemplate(template *ProjectTemplate) error {
+	return r.db.Delete(template).Error
+}
+
+func (r *projectRepository) FindCustomFields(projectID uint) ([]tasks.CustomField, error) {
+	var fields []tasks.CustomField
+	err := r.db.Where("project_id = ?", projectID).Order("index asc, id asc").Find(&fields).Error
+	return fields, err
 }
 
-func (r *projectRepository) DeleteTasksByProject(projectID uint) error {
-	return r.db.Where("project_id = ?", projectID).Delete(&tasks.Task{}).Error
+func (r *projectRepository) FindStatuses(projectID uint) ([]tasks.Status, error) {
+	var statuses []tasks.Status
+	err := r.db.Where("project_id = ?", projectID).Order("index asc").Find(&statuses).Error
+	return statuses, err
+}
+
+func (r *projectRepository) FindTasksWithDetails(projectID uint) ([]tasks.Task, error) {
+	var projectTasks []tasks.Task
+	err := r.db.Preload("Status").
+		Preload("Priority").
+		Where("project_id = ?", projectID).
+		Order("created_at asc").
+		Find(&projectTasks).Error
+	return projectTasks, err
+}
+
+// FindLabels returns the project's own labels
+func (r *projectRepository) FindLabels(projectID uint) ([]tasks.Label, error) {
+	var labels []tasks.Label
+	err := r.db.Where("project_id = ?", projectID).Order("LOWER(name) asc").Find(&labels).Error
+	return labels, err
+}
+
+// FindTaskLabelNames maps each task of the project to the names of its labels
+func (r *projectRepository) FindTaskLabelNames(projectID uint) (map[uint][]string, error) {
+	var rows []struct {
+		TaskID uint
+		Name   string
+	}
+	err := r.db.Table("task_labels").
+		Select("task_labels.task_id, labels.name").
+		Joins("JOIN labels ON labels.id = task_labels.label_id").
+		Joins("JOIN tasks ON tasks.id = task_labels.task_id").
+		Where("tasks.project_id = ? AND tasks.deleted_at IS NULL", projectID).
+		Order("LOWER(labels.name) asc").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint][]string)
+	for _, row := range rows {
+		names[row.TaskID] = append(names[row.TaskID], row.Name)
+	}
+	return names, nil
+}
+
+// Helper: creates the template's statuses, labels and starter tasks in the project
+func applyTemplate(tx *gorm.DB, projectID uint, template *ProjectTemplate, startDate time.Time, creatorID uint) error {
+	statusIDs := make(map[string]uint)
+	doneStatuses := make(map[uint]bool)
+	var firstStatusID uint
+	for i, ts := range template.Statuses {
+		category := ts.Category
+		if category == "" {
+			category = tasks.StatusCategoryTodo
+		}
+		status := tasks.Status{
+			Name:      ts.Name,
+			Index:     i,
+			Category:  category,
+			ProjectID: int(projectID),
+		}
+		if err := tx.Create(&status).Error; err != nil {
+			return err
+		}
+		statusIDs[ts.Name] = status.ID
+		doneStatuses[status.ID] = category == tasks.StatusCategoryDone
+		if i == 0 {
+			firstStatusID = status.ID
+		}
+	}
+
+	for i, tf := range template.CustomFields {
+		field := tasks.CustomField{
+			ProjectID: projectID,
+			Name:      tf.Name,
+			Type:      tf.Type,
+			Options:   tf.Options,
+			Required:  tf.Required,
+			Index:     i,
+		}
+		if err := tx.Create(&field).Error; err != nil {
+			return err
+		}
+	}
+
+	// Task labels resolve to the template's labels first, then to the organization's
+	labelIDs := make(map[string]uint)
+	for _, tl := range template.Labels {
+		color := strings.ToUpper(tl.Color)
+		if color == "" {
+			color = tasks.DefaultLabelColor
+		}
+		label := tasks.Label{OrganizationID: template.OrganizationID, ProjectID: &projectID, Name: tl.Name, Color: color}
+		if err := tx.Create(&label).Error; err != nil {
+			return err
+		}
+		labelIDs[strings.ToLower(tl.Name)] = label.ID
+	}
+	var orgLabels []tasks.Label
+	if err := tx.Where("organization_id = ? AND project_id IS NULL", template.OrganizationID).Find(&orgLabels).Error; err != nil {
+		return err
+	}
+	for _, l := range orgLabels {
+		if _, ok := labelIDs[strings.ToLower(l.Name)]; !ok {
+			labelIDs[strings.ToLower(l.Name)] = l.ID
+		}
+	}
+
+	var priorities []tasks.Priority
+	if err := tx.Find(&priorities).Error; err != nil {
+		return err
+	}
+	priorityIDs := make(map[string]uint)
+	for _, p := range priorities {
+		priorityIDs[p.Name] = p.ID
+	}
+
+	for _, tt := range template.Tasks {
+		statusID, ok := statusIDs[tt.StatusName]
+		if !ok {
+			statusID = firstStatusID
+		}
+		priorityID, ok := priorityIDs[tt.PriorityName]
+		if !ok {
+			priorityID = priorityIDs["Medium"]
+		}
+
+		number, err := models.NextTaskNumber(tx, projectID)
+		if err != nil {
+			return err
+		}
+
+		task := tasks.Task{
+			Number:     number,
+			Title:      tt.Title,
+			ProjectID:  projectID,
+			StatusID:   statusID,
+			PriorityID: priorityID,
+			StartDate:  offsetDate(startDate, tt.StartOffsetDays),
+			EndDate:    offsetDate(startDate, tt.EndOffsetDays),
+
+			// The new project has no members or tasks to link to yet
+			Description:     tt.Description,
+			DescriptionHTML: utils.RenderMarkdown(tt.Description, nil),
+			MentionIDs:      []uint{},
+			ReferencedKeys:  []string{},
+		}
+		if doneStatuses[statusID] {
+			now := time.Now()
+			task.CompletedAt = &now
+		}
+		if tt.EstimateMinutes != nil && *tt.EstimateMinutes > 0 {
+			task.EstimateMinutes = tt.EstimateMinutes
+			task.RemainingMinutes = tt.EstimateMinutes
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		transition := tasks.InitialTransition(&task, creatorID)
+		if err := tx.Create(&transition).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&tasks.TaskWatcher{TaskID: task.ID, UserID: creatorID}).Error; err != nil {
+			return err
+		}
+
+		added := make(map[uint]bool)
+		for _, name := range tt.Labels {
+			labelID, ok := labelIDs[strings.ToLower(name)]
+			if !ok || added[labelID] {
+				continue
+			}
+			added[labelID] = true
+			if err := tx.Create(&tasks.TaskLabel{TaskID: task.ID, LabelID: labelID}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CloneProject deep-copies members and statuses (and optionally tasks and assignees)
+// into target, remapping status IDs to the new project's statuses
+func (r *projectRepository) CloneProject(source *Project, target *Project, creatorID uint, options CloneProjectInput) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganizations(tx, target.OrganizationID); err != nil {
+			return err
+		}
+		key, err := claimKey(tx, target.OrganizationID, target.Key, false)
+		if err != nil {
+			return err
+		}
+		target.Key = key
+		if err := tx.Create(target).Error; err != nil {
+			return err
+		}
+
+		// 1. Members (the person cloning always leads the copy)
+		var members []ProjectMember
+		if err := tx.Where("project_id = ? AND user_id <> ?", source.ID, creatorID).Find(&members).Error; err != nil {
+			return err
+		}
+		members = append(members, ProjectMember{UserID: creatorID, Role: models.ProjectRoleLead})
+		for _, m := range members {
+			member := ProjectMember{ProjectID: target.ID, UserID: m.UserID, Role: m.Role}
+			if err := tx.Create(&member).Error; err != nil {
+				return err
+			}
+		}
+
+		// 2. Statuses
+		var statuses []tasks.Status
+		if err := tx.Where("project_id = ?", source.ID).Order("index asc").Find(&statuses).Error; err != nil {
+			return err
+		}
+		statusMap := make(map[uint]uint)
+		var firstStatusID uint
+		for i, st := range statuses {
+			status := tasks.Status{Name: st.Name, Index: st.Index, Category: st.Category, ProjectID: int(target.ID)}
+			if err := tx.Create(&status).Error; err != nil {
+				return err
+			}
+			statusMap[st.ID] = status.ID
+			if i == 0 {
+				firstStatusID = status.ID
+			}
+		}
+
+		// 3. Custom fields
+		var fields []tasks.CustomField
+		if err := tx.Where("project_id = ?", source.ID).Order("index asc").Find(&fields).Error; err != nil {
+			return err
+		}
+		fieldMap := make(map[string]string)
+		for _, f := range fields {
+			field := tasks.CustomField{
+				ProjectID: target.ID,
+				Name:      f.Name,
+				Type:      f.Type,
+				Options:   f.Options,
+				Required:  f.Required,
+				Index:     f.Index,
+			}
+			if err := tx.Create(&field).Error; err != nil {
+				return err
+			}
+			fieldMap[interfaceToString(f.ID)] = interfaceToString(field.ID)
+		}
+
+		// Project labels; organization labels are shared and need no copy
+		var labels []tasks.Label
+		if err := tx.Where("project_id = ?", source.ID).Order("id asc").Find(&labels).Error; err != nil {
+			return err
+		}
+		labelMap := make(map[uint]uint)
+		for _, l := range labels {
+			label := tasks.Label{OrganizationID: target.OrganizationID, ProjectID: &target.ID, Name: l.Name, Color: l.Color}
+			if err := tx.Create(&label).Error; err != nil {
+				return err
+			}
+			labelMap[l.ID] = label.ID
+		}
+
+		if !options.IncludeTasks {
+			return nil
+		}
+
+		// 4. Tasks
+		var sourceTasks []tasks.Task
+		if err := tx.Where("project_id = ?", source.ID).Order("created_at asc").Find(&sourceTasks).Error; err != nil {
+			return err
+		}
+		taskMap := make(map[uint]uint)
+		for _, t := range sourceTasks {
+			statusID, ok := statusMap[t.StatusID]
+			if !ok {
+				statusID = firstStatusID
+			}
+			number, err := models.NextTaskNumber(tx, target.ID)
+			if err != nil {
+				return err
+			}
+
+			// Values are keyed by field ID, so they follow the copied fields
+			customFields := make(tasks.CustomFieldValues)
+			for key, value := range t.CustomFields {
+				if newKey, ok := fieldMap[key]; ok {
+					customFields[newKey] = value
+				}
+			}
+
+			task := tasks.Task{
+				Number:       number,
+				Title:        t.Title,
+				ProjectID:    target.ID,
+				StatusID:     statusID,
+				PriorityID:   t.PriorityID,
+				CustomFields: customFields,
+				StartDate:    t.StartDate,
+				EndDate:      t.EndDate,
+				CompletedAt:  t.CompletedAt,
+
+				EstimateMinutes:  t.EstimateMinutes,
+				RemainingMinutes: t.RemainingMinutes,
+
+				Description:     t.Description,
+				DescriptionHTML: t.DescriptionHTML,
+				MentionIDs:      t.MentionIDs,
+				ReferencedKeys:  t.ReferencedKeys,
+			}
+			if err := tx.Create(&task).Error; err != nil {
+				return err
+			}
+			transition := tasks.InitialTransition(&task, creatorID)
+			if err := tx.Create(&transition).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&tasks.TaskWatcher{TaskID: task.ID, UserID: creatorID}).Error; err != nil {
+				return err
+			}
+			taskMap[t.ID] = task.ID
+		}
+
+		// Links between copied tasks are copied too; links to other tasks stay with the source
+		copiedIDs := make([]uint, 0, len(taskMap))
+		for id := range taskMap {
+			copiedIDs = append(copiedIDs, id)
+		}
+		var dependencies []tasks.TaskDependency
+		if err := tx.Where("blocker_id IN ? AND blocked_id IN ?", copiedIDs, copiedIDs).Find(&dependencies).Error; err != nil {
+			return err
+		}
+		for _, d := range dependencies {
+			copied := tasks.TaskDependency{BlockerID: taskMap[d.BlockerID], BlockedID: taskMap[d.BlockedID], CreatedBy: creatorID}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+		}
+
+		// Checklists; their assignees only come along with the task assignees
+		var items []tasks.ChecklistItem
+		if err := tx.Where("task_id IN ?", copiedIDs).Order("task_id asc, position asc").Find(&items).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			copied := tasks.ChecklistItem{
+				TaskID:   taskMap[item.TaskID],
+				Position: item.Position,
+				Text:     item.Text,
+				Done:     item.Done,
+				DueDate:  item.DueDate,
+			}
+			if options.IncludeAssignees {
+				copied.AssigneeID = item.AssigneeID
+			}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+		}
+
+		var taskLabels []tasks.TaskLabel
+		if err := tx.Where("task_id IN ?", copiedIDs).Find(&taskLabels).Error; err != nil {
+			return err
+		}
+		for _, tl := range taskLabels {
+			labelID, ok := labelMap[tl.LabelID]
+			if !ok {
+				labelID = tl.LabelID
+			}
+			copied := tasks.TaskLabel{TaskID: taskMap[tl.TaskID], LabelID: labelID}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+		}
+
+		// Subtasks hang under the copies of their parents
+		for _, t := range sourceTasks {
+			if t.ParentID == nil {
+				continue
+			}
+			if parentID, ok := taskMap[*t.ParentID]; ok {
+				if err := tx.Model(&tasks.Task{}).Where("id = ?", taskMap[t.ID]).Update("parent_id", parentID).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if !options.IncludeAssignees || len(taskMap) == 0 {
+			return nil
+		}
+
+		// 5. Assignees
+		var sourceIDs []uint
+		for id := range taskMap {
+			sourceIDs = append(sourceIDs, id)
+		}
+		var links []tasks.TaskUser
+		if err := tx.Where("task_id IN ?", sourceIDs).Find(&links).Error; err != nil {
+			return err
+		}
+		for _, link := range links {
+			clone := tasks.TaskUser{TaskID: taskMap[link.TaskID], UserID: link.UserID}
+			if err := tx.Create(&clone).Error; err != nil {
+				return err
+			}
+			// Copied assignees watch their tasks like assigning them would
+			watcher := tasks.TaskWatcher{TaskID: clone.TaskID, UserID: clone.UserID}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&watcher).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindUsersOutsideOrg lists assignees and members of the project who do not belong to orgID
+func (r *projectRepository) FindUsersOutsideOrg(projectID uint, orgID uint) ([]MoveConflict, error) {
+	var conflicts []MoveConflict
+	err := r.db.Raw(`
+		SELECT users.id AS user_id, users.email,
+			(SELECT COUNT(*) FROM task_users JOIN tasks ON tasks.id = task_users.task_id
+				WHERE tasks.project_id = ? AND task_users.user_id = users.id) AS assigned_task_count,
+			COALESCE((SELECT role FROM project_members
+				WHERE project_members.project_id = ? AND project_members.user_id = users.id), '') AS project_role
+		FROM users
+		WHERE users.id IN (
+			SELECT task_users.user_id FROM task_users JOIN tasks ON tasks.id = task_users.task_id WHERE tasks.project_id = ?
+			UNION
+			SELECT user_id FROM project_members WHERE project_id = ?
+			UNION
+			SELECT checklist_items.assignee_id FROM checklist_items JOIN tasks ON tasks.id = checklist_items.task_id
+			WHERE tasks.project_id = ? AND checklist_items.assignee_id IS NOT NULL
+			UNION
+			SELECT task_watchers.user_id FROM task_watchers JOIN tasks ON tasks.id = task_watchers.task_id WHERE tasks.project_id = ?
+		)
+		AND users.id NOT IN (SELECT user_id FROM organization_users WHERE organization_id = ?)
+		ORDER BY users.email`,
+		projectID, projectID, projectID, projectID, projectID, projectID, orgID,
+	).Scan(&conflicts).Error
+	return conflicts, err
+}
+
+// MoveProject re-homes the project (statuses and tasks follow through project_id) and
+// resolves the conflicting users by unassigning them or inviting them, atomically
+func (r *projectRepository) MoveProject(project *Project, destOrgID uint, key string, assigneeAction string, userIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// The source org is locked like when dependencies are added, the destination
+		// while the key is settled
+		if err := lockOrganizations(tx, project.OrganizationID, destOrgID); err != nil {
+			return err
+		}
+		key, err := claimKey(tx, destOrgID, key, false)
+		if err != nil {
+			return err
+		}
+
+		if len(userIDs) > 0 {
+			switch assigneeAction {
+			case MoveActionUnassign:
+				if err := tx.Exec("DELETE FROM task_users WHERE user_id IN ? AND task_id IN (SELECT id FROM tasks WHERE project_id = ?)", userIDs, project.ID).Error; err != nil {
+					return err
+				}
+				if err := tx.Exec("UPDATE checklist_items SET assignee_id = NULL WHERE assignee_id IN ? AND task_id IN (SELECT id FROM tasks WHERE project_id = ?)", userIDs, project.ID).Error; err != nil {
+					return err
+				}
+				if err := tx.Exec("DELETE FROM task_watchers WHERE user_id IN ? AND task_id IN (SELECT id FROM tasks WHERE project_id = ?)", userIDs, project.ID).Error; err != nil {
+					return err
+				}
+				if err := tx.Where("project_id = ? AND user_id IN ?", project.ID, userIDs).Delete(&ProjectMember{}).Error; err != nil {
+					return err
+				}
+			case MoveActionInvite:
+				for _, uid := range userIDs {
+					if err := tx.Table("organization_users").Create(map[string]interface{}{
+						"organization_id": destOrgID,
+						"user_id":         uid,
+						"role":            models.OrgRoleMember,
+						"created_at":      time.Now(),
+					}).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// The project's own labels move with it. Organization labels of the source org
+		// used by its tasks are swapped for the destination's label of the same name,
+		// copied over when there is none.
+		if err := tx.Model(&tasks.Label{}).Where("project_id = ?", project.ID).
+			Update("organization_id", destOrgID).Error; err != nil {
+			return err
+		}
+		var used []tasks.Label
+		if err := tx.Raw(`
+			SELECT DISTINCT labels.* FROM labels
+			JOIN task_labels ON task_labels.label_id = labels.id
+			JOIN tasks ON tasks.id = task_labels.task_id
+			WHERE tasks.project_id = ? AND labels.project_id IS NULL AND labels.organization_id <> ?`,
+			project.ID, destOrgID).Scan(&used).Error; err != nil {
+			return err
+		}
+		for _, label := range used {
+			var target tasks.Label
+			err := tx.Where("organization_id = ? AND project_id IS NULL AND LOWER(name) = LOWER(?)", destOrgID, label.Name).
+				First(&target).Error
+			if err == gorm.ErrRecordNotFound {
+				target = tasks.Label{OrganizationID: destOrgID, Name: label.Name, Color: label.Color}
+				err = tx.Create(&target).Error
+			}
+			if err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE task_labels SET label_id = ? WHERE label_id = ? AND task_id IN (SELECT id FROM tasks WHERE project_id = ?)",
+				target.ID, label.ID, project.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		// Dependencies cannot cross organizations, so links to the source org's other
+		// projects are dropped
+		if err := tx.Exec(`
+			DELETE FROM task_dependencies
+			WHERE (blocker_id IN (SELECT id FROM tasks WHERE project_id = ?)) <> (blocked_id IN (SELECT id FROM tasks WHERE project_id = ?))`,
+			project.ID, project.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(project).Updates(map[string]interface{}{
+			"organization_id": destOrgID,
+			"key":             key,
+		}).Error
+	})
+}
+
+// TrashProject soft-deletes the project and its live tasks with the same timestamp,
+// so a restore brings back exactly the tasks that went to the trash with it
+func (r *projectRepository) TrashProject(project *Project) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&tasks.Task{}).
+			Where("project_id = ?", project.ID).
+			Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(project).Update("deleted_at", now).Error
+	})
+}
+
+func (r *projectRepository) FindTrashedByIDAndOrg(id string, orgID string) (*Project, error) {
+	var project Project
+	err := r.db.Unscoped().
+		Where("id = ? AND organization_id = ? AND deleted_at IS NOT NULL", id, orgID).
+		First(&project).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *projectRepository) RestoreProject(project *Project) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&tasks.Task{}).
+			Where("project_id = ? AND deleted_at = ?", project.ID, project.DeletedAt.Time).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(project).Update("deleted_at", nil).Error
+	})
+}
+
+func (r *projectRepository) FindTrashedProjects(orgID string, userID uint) ([]TrashedProject, error) {
+	var trashed []TrashedProject
+	err := r.db.Unscoped().Model(&Project{}).
+		Select("projects.*, (SELECT COUNT(*) FROM tasks WHERE tasks.project_id = projects.id AND tasks.deleted_at = projects.deleted_at) AS task_count").
+		Scopes(models.ByOrg(orgID), models.VisibleToUser(userID)).
+		Where("projects.deleted_at IS NOT NULL").
+		Order("projects.deleted_at desc").
+		Scan(&trashed).Error
+	return trashed, err
+}
+
+// FindTrashedTasks lists tasks deleted on their own from projects that are still alive
+func (r *projectRepository) FindTrashedTasks(orgID string, userID uint) ([]tasks.Task, error) {
+	var trashed []tasks.Task
+	err := r.db.Unscoped().
+		Preload("Status").
+		Preload("Priority").
+		Joins("JOIN projects ON projects.id = tasks.project_id AND projects.deleted_at IS NULL").
+		Scopes(models.VisibleToUser(userID)).
+		Where("projects.organization_id = ? AND tasks.deleted_at IS NOT NULL", orgID).
+		Order("tasks.deleted_at desc").
+		Find(&trashed).Error
+	return trashed, err
+}
+
+// PurgeTrash permanently removes projects and tasks that have been in the trash since before cutoff
+func (r *projectRepository) PurgeTrash(cutoff time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var projectIDs []uint
+		if err := tx.Unscoped().Model(&Project{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Pluck("id", &projectIDs).Error; err != nil {
+			return err
+		}
+
+		if len(projectIDs) > 0 {
+			if err := tx.Exec("DELETE FROM task_users WHERE task_id IN (SELECT id FROM tasks WHERE project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM task_status_transitions WHERE task_id IN (SELECT id FROM tasks WHERE project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM task_dependencies WHERE blocker_id IN (SELECT id FROM tasks WHERE project_id IN ?) OR blocked_id IN (SELECT id FROM tasks WHERE project_id IN ?)", projectIDs, projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM notifications WHERE task_id IN (SELECT id FROM tasks WHERE project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM comment_revisions WHERE comment_id IN (SELECT comments.id FROM comments JOIN tasks ON tasks.id = comments.task_id WHERE tasks.project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM comments WHERE task_id IN (SELECT id FROM tasks WHERE project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM checklist_items WHERE task_id IN (SELECT id FROM tasks WHERE project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM task_labels WHERE task_id IN (SELECT id FROM tasks WHERE project_id IN ?) OR label_id IN (SELECT id FROM labels WHERE project_id IN ?)", projectIDs, projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM task_watchers WHERE task_id IN (SELECT id FROM tasks WHERE project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM time_entries WHERE task_id IN (SELECT id FROM tasks WHERE project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM sprint_tasks WHERE sprint_id IN (SELECT id FROM sprints WHERE project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM sprint_snapshots WHERE sprint_id IN (SELECT id FROM sprints WHERE project_id IN ?)", projectIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&StatusSnapshot{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("project_id IN ?", projectIDs).Delete(&tasks.Task{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&tasks.Status{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&tasks.CustomField{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&tasks.Label{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&ProjectMember{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&ProjectFavorite{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&ProjectView{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&Milestone{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&Sprint{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("id IN ?", projectIDs).Delete(&Project{}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Tasks trashed on their own
+		if err := tx.Exec("DELETE FROM task_users WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM sprint_tasks WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM task_status_transitions WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM task_dependencies WHERE blocker_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?) OR blocked_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff, cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM notifications WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM comment_revisions WHERE comment_id IN (SELECT comments.id FROM comments JOIN tasks ON tasks.id = comments.task_id WHERE tasks.deleted_at IS NOT NULL AND tasks.deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM comments WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM checklist_items WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM task_labels WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM task_watchers WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM time_entries WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", cutoff).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Delete(&tasks.Task{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		purged = int64(len(projectIDs)) + result.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+// Helper: nil offsets stay nil, otherwise the date is shifted by whole days
+func offsetDate(start time.Time, days *int) *time.Time {
+	if days == nil {
+		return nil
+	}
+	date := start.AddDate(0, 0, *days)
+	return &date
 }
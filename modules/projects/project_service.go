@@ -2,15 +2,69 @@ package projects
 
 import (
 	"errors"
+	"gotask-backend/models"
 	"gotask-backend/modules/tasks"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type ProjectService interface {
-	GetProjects(orgID string) ([]Project, error)
+	GetProjects(orgID string, userID uint, options ProjectListOptions) ([]ProjectListItem, error)
+	GetProject(id string, orgID string, userID uint) (*ProjectDetail, error)
 	CreateProject(input CreateProjectInput, userID uint) (*Project, error)
-	DeleteProject(id string, orgID string) error
+	UpdateProject(id string, orgID string, userID uint, input UpdateProjectInput) (*Project, error)
+	ArchiveProject(id string, orgID string, userID uint) (*Project, error)
+	UnarchiveProject(id string, orgID string, userID uint) (*Project, error)
+	DeleteProject(id string, orgID string, userID uint) error
+
+	// Favorites
+	FavoriteProject(id string, orgID string, userID uint) error
+	UnfavoriteProject(id string, orgID string, userID uint) error
+
+	// Trash
+	RestoreProject(id string, orgID string, userID uint) (*Project, error)
+	GetTrash(orgID string, userID uint) (*Trash, error)
+	PurgeTrash(retention time.Duration) (int64, error)
+
+	// Membership
+	AuthorizeProject(id string, orgID string, userID uint, minRole string) (*Project, string, error)
+	GetMembers(id string, orgID string, userID uint) ([]ProjectMemberDetail, error)
+	AddMember(id string, orgID string, userID uint, input ProjectMemberInput) error
+	UpdateMemberRole(id string, orgID string, userID uint, input ProjectMemberInput) error
+	RemoveMember(id string, orgID string, userID uint, memberID uint) error
+
+	// Templates & cloning
+	GetTemplates(orgID string) ([]ProjectTemplate, error)
+	GetTemplate(id string, orgID string) (*ProjectTemplate, error)
+	CreateTemplate(input ProjectTemplate, userID uint) (*ProjectTemplate, error)
+	SaveAsTemplate(id string, orgID string, userID uint, input SaveAsTemplateInput) (*ProjectTemplate, error)
+	DeleteTemplate(id string, orgID string, userID uint) error
+	CloneProject(id string, orgID string, userID uint, input CloneProjectInput) (*Project, error)
+
+	MoveProject(id string, orgID string, userID uint, input MoveProjectInput) (*MoveProjectResult, error)
 }
 
+var (
+	ErrProjectNotFound  = errors.New("project not found or access denied")
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrProjectForbidden = errors.New("you do not have the required project role for this action")
+	ErrInvalidKey       = errors.New("project key must be 2-10 characters: an uppercase letter followed by letters or digits")
+	ErrKeyTaken         = errors.New("project key is already used in this organization")
+	ErrOrgAdminRequired = errors.New("you must be an admin of both organizations to move a project")
+	ErrMoveConflicts    = errors.New("some assignees or members are not in the destination organization; choose assignee_action 'unassign' or 'invite'")
+)
+
+// How MoveProject resolves users who are not in the destination organization
+const (
+	MoveActionUnassign = "unassign"
+	MoveActionInvite   = "invite"
+)
+
+var projectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
 type projectService struct {
 	repo        ProjectRepository
 	taskService tasks.TaskService
@@ -20,51 +74,736 @@ func NewProjectService(repo ProjectRepository, taskService tasks.TaskService) Pr
 	return &projectService{repo, taskService}
 }
 
+// Sort orders for GET /projects
+const (
+	ProjectSortCreated = "created"
+	ProjectSortName    = "name"
+	ProjectSortRecent  = "recent" // last viewed by the caller first
+)
+
 // Input DTO
+type ProjectListOptions struct {
+	IncludeArchived bool
+	FavoritesOnly   bool
+	Sort            string
+}
+
 type CreateProjectInput struct {
+	Key            string
 	Name           string
 	Description    string
+	Visibility     string
 	OrganizationID uint
+	TemplateID     uint
+}
+
+type UpdateProjectInput struct {
+	Name        *string
+	Description *string
+	Visibility  *string
+}
+
+type ProjectMemberInput struct {
+	UserID uint
+	Role   string
+}
+
+type SaveAsTemplateInput struct {
+	Name         string
+	Description  string
+	IncludeTasks bool
+}
+
+type CloneProjectInput struct {
+	Name             string
+	IncludeTasks     bool
+	IncludeAssignees bool
+}
+
+type MoveProjectInput struct {
+	DestinationOrgID uint
+	AssigneeAction   string
+	DryRun           bool
+}
+
+func isValidVisibility(visibility string) bool {
+	return visibility == models.ProjectVisibilityOrg || visibility == models.ProjectVisibilityPrivate
+}
+
+// AuthorizeProject finds a project in the org and checks the caller's effective role.
+// Projects the caller cannot see are reported as not found.
+func (s *projectService) AuthorizeProject(id string, orgID string, userID uint, minRole string) (*Project, string, error) {
+	project, err := s.repo.FindByIDAndOrg(id, orgID)
+	if err != nil {
+		return nil, "", ErrProjectNotFound
+	}
+
+	role, err := s.checkRole(project, userID, minRole)
+	if err != nil {
+		return nil, role, err
+	}
+	return project, role, nil
+}
+
+// Helper: resolves the caller's effective role on an already loaded project
+func (s *projectService) checkRole(project *Project, userID uint, minRole string) (string, error) {
+	memberRole, err := s.repo.FindMemberRole(project.ID, userID)
+	if err != nil {
+		return "", err
+	}
+	isAdmin, err := s.repo.IsOrgAdmin(project.OrganizationID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	role := models.EffectiveProjectRole(project.Visibility, memberRole, isAdmin)
+	if role == "" {
+		return "", ErrProjectNotFound
+	}
+	if !models.HasProjectRole(role, minRole) {
+		return role, ErrProjectForbidden
+	}
+	return role, nil
 }
 
-func (s *projectService) GetProjects(orgID string) ([]Project, error) {
-	return s.repo.FindAllByOrg(orgID)
+func (s *projectService) GetProjects(orgID string, userID uint, options ProjectListOptions) ([]ProjectListItem, error) {
+	return s.repo.FindAllByOrg(orgID, userID, options)
+}
+
+func (s *projectService) GetProject(id string, orgID string, userID uint) (*ProjectDetail, error) {
+	project, role, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	taskCount, err := s.repo.CountTasks(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Feeds ?sort=recent on the project list; a failed write should not hide the project
+	_ = s.repo.RecordView(project.ID, userID)
+
+	statuses, err := s.repo.CountTasksPerStatus(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectDetail{
+		Project:     *project,
+		Role:        role,
+		StatusCount: int64(len(statuses)),
+		TaskCount:   taskCount,
+		Statuses:    statuses,
+	}, nil
 }
 
 func (s *projectService) CreateProject(input CreateProjectInput, userID uint) (*Project, error) {
+	if input.Visibility == "" {
+		input.Visibility = models.ProjectVisibilityOrg
+	}
+	if !isValidVisibility(input.Visibility) {
+		return nil, errors.New("visibility must be 'org' or 'private'")
+	}
+
+	var template *ProjectTemplate
+	if input.TemplateID != 0 {
+		found, err := s.repo.FindTemplateByIDAndOrg(interfaceToString(input.TemplateID), interfaceToString(input.OrganizationID))
+		if err != nil {
+			return nil, ErrTemplateNotFound
+		}
+		template = found
+	}
+
+	key, err := s.resolveKey(input.OrganizationID, input.Key, input.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	project := Project{
+		Key:            key,
 		Name:           input.Name,
 		Description:    input.Description,
+		Visibility:     input.Visibility,
 		OrganizationID: input.OrganizationID,
 	}
 
-	if err := s.repo.Create(&project); err != nil {
+	// Creator leads the project
+	if err := s.repo.Create(&project, input.Key != "", userID, template); err != nil {
 		return nil, err
 	}
 
-	if err := s.taskService.CreateDefaultStatuses(project.ID); err != nil {
+	// Re-fetch to populate relations (optional)
+	return &project, nil
+}
+
+func (s *projectService) UpdateProject(id string, orgID string, userID uint, input UpdateProjectInput) (*Project, error) {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
 		return nil, err
 	}
 
-	// Re-fetch to populate relations (optional)
-	return &project, nil
+	updates := make(map[string]interface{})
+	if input.Name != nil {
+		if *input.Name == "" {
+			return nil, errors.New("project name cannot be empty")
+		}
+		updates["name"] = *input.Name
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.Visibility != nil {
+		if !isValidVisibility(*input.Visibility) {
+			return nil, errors.New("visibility must be 'org' or 'private'")
+		}
+		updates["visibility"] = *input.Visibility
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.Update(project, updates); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.FindByIDAndOrg(id, orgID)
 }
 
-func (s *projectService) DeleteProject(id string, orgID string) error {
-	// 1. Security: Find Project AND ensure it belongs to the Context Org
-	project, err := s.repo.FindByIDAndOrg(id, orgID)
+func (s *projectService) ArchiveProject(id string, orgID string, userID uint) (*Project, error) {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+	if project.ArchivedAt != nil {
+		return nil, errors.New("project is already archived")
+	}
+
+	if err := s.repo.Update(project, map[string]interface{}{"archived_at": time.Now()}); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByIDAndOrg(id, orgID)
+}
+
+func (s *projectService) UnarchiveProject(id string, orgID string, userID uint) (*Project, error) {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+	if project.ArchivedAt == nil {
+		return nil, errors.New("project is not archived")
+	}
+
+	if err := s.repo.Update(project, map[string]interface{}{"archived_at": nil}); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByIDAndOrg(id, orgID)
+}
+
+// DeleteProject moves the project and its tasks to the trash; PurgeTrash removes them for good
+func (s *projectService) DeleteProject(id string, orgID string, userID uint) error {
+	// 1. Security: Find Project AND ensure the caller leads it
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return err
+	}
+
+	// 2. Soft delete (project + tasks in one transaction)
+	return s.repo.TrashProject(project)
+}
+
+func (s *projectService) RestoreProject(id string, orgID string, userID uint) (*Project, error) {
+	project, err := s.repo.FindTrashedByIDAndOrg(id, orgID)
+	if err != nil {
+		return nil, ErrProjectNotFound
+	}
+	if _, err := s.checkRole(project, userID, models.ProjectRoleLead); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RestoreProject(project); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByIDAndOrg(id, orgID)
+}
+
+func (s *projectService) GetTrash(orgID string, userID uint) (*Trash, error) {
+	trashedProjects, err := s.repo.FindTrashedProjects(orgID, userID)
 	if err != nil {
-		return errors.New("project not found or access denied")
+		return nil, err
+	}
+	trashedTasks, err := s.repo.FindTrashedTasks(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &Trash{Projects: trashedProjects, Tasks: trashedTasks}, nil
+}
+
+func (s *projectService) PurgeTrash(retention time.Duration) (int64, error) {
+	return s.repo.PurgeTrash(time.Now().Add(-retention))
+}
+
+func (s *projectService) GetMembers(id string, orgID string, userID uint) ([]ProjectMemberDetail, error) {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindMembers(project.ID)
+}
+
+func (s *projectService) AddMember(id string, orgID string, userID uint, input ProjectMemberInput) error {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return err
+	}
+	if !models.IsValidProjectRole(input.Role) {
+		return errors.New("role must be 'lead', 'contributor' or 'viewer'")
+	}
+
+	// Only people inside the organization can join its projects
+	isOrgMember, err := s.repo.IsOrgMember(project.OrganizationID, input.UserID)
+	if err != nil {
+		return err
+	}
+	if !isOrgMember {
+		return errors.New("user is not a member of this organization")
 	}
 
-	// 2. Cleanup
-	if err := s.repo.ClearTaskAssignees(project.ID); err != nil {
+	existingRole, err := s.repo.FindMemberRole(project.ID, input.UserID)
+	if err != nil {
 		return err
 	}
-	if err := s.repo.DeleteTasksByProject(project.ID); err != nil {
+	if existingRole != "" {
+		return errors.New("user is already a member of this project")
+	}
+
+	return s.repo.AddMember(&ProjectMember{
+		ProjectID: project.ID,
+		UserID:    input.UserID,
+		Role:      input.Role,
+	})
+}
+
+func (s *projectService) UpdateMemberRole(id string, orgID string, userID uint, input ProjectMemberInput) error {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
 		return err
 	}
+	if !models.IsValidProjectRole(input.Role) {
+		return errors.New("role must be 'lead', 'contributor' or 'viewer'")
+	}
+
+	existingRole, err := s.repo.FindMemberRole(project.ID, input.UserID)
+	if err != nil {
+		return err
+	}
+	if existingRole == "" {
+		return errors.New("user is not a member of this project")
+	}
+
+	return s.repo.UpdateMemberRole(project.ID, input.UserID, input.Role)
+}
+
+func (s *projectService) RemoveMember(id string, orgID string, userID uint, memberID uint) error {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return err
+	}
+
+	existingRole, err := s.repo.FindMemberRole(project.ID, memberID)
+	if err != nil {
+		return err
+	}
+	if existingRole == "" {
+		return errors.New("user is not a member of this project")
+	}
+
+	return s.repo.RemoveMember(project.ID, memberID)
+}
+
+func (s *projectService) FavoriteProject(id string, orgID string, userID uint) error {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return err
+	}
+	return s.repo.AddFavorite(project.ID, userID)
+}
+
+func (s *projectService) UnfavoriteProject(id string, orgID string, userID uint) error {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return err
+	}
+	return s.repo.RemoveFavorite(project.ID, userID)
+}
+
+func (s *projectService) GetTemplates(orgID string) ([]ProjectTemplate, error) {
+	return s.repo.FindTemplatesByOrg(orgID)
+}
+
+func (s *projectService) GetTemplate(id string, orgID string) (*ProjectTemplate, error) {
+	template, err := s.repo.FindTemplateByIDAndOrg(id, orgID)
+	if err != nil {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+func (s *projectService) CreateTemplate(input ProjectTemplate, userID uint) (*ProjectTemplate, error) {
+	if err := validateTemplate(&input); err != nil {
+		return nil, err
+	}
+
+	template := ProjectTemplate{
+		OrganizationID: input.OrganizationID,
+		Name:           input.Name,
+		Description:    input.Description,
+		Statuses:       input.Statuses,
+		Labels:         input.Labels,
+		CustomFields:   input.CustomFields,
+		Tasks:          input.Tasks,
+		CreatedBy:      userID,
+	}
+
+	if err := s.repo.CreateTemplate(&template); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// SaveAsTemplate publishes a project's setup to the whole organization, so only its leads may
+func (s *projectService) SaveAsTemplate(id string, orgID string, userID uint, input SaveAsTemplateInput) (*ProjectTemplate, error) {
+	project, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := s.repo.FindStatuses(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := ProjectTemplate{
+		OrganizationID: project.OrganizationID,
+		Name:           input.Name,
+		Description:    input.Description,
+	}
+	for _, st := range statuses {
+		template.Statuses = append(template.Statuses, TemplateStatus{Name: st.Name, Category: st.Category})
+	}
+
+	fields, err := s.repo.FindCustomFields(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		template.CustomFields = append(template.CustomFields, TemplateCustomField{
+			Name:     f.Name,
+			Type:     f.Type,
+			Options:  f.Options,
+			Required: f.Required,
+		})
+	}
+
+	labels, err := s.repo.FindLabels(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labels {
+		template.Labels = append(template.Labels, TemplateLabel{Name: l.Name, Color: l.Color})
+	}
+
+	if input.IncludeTasks {
+		projectTasks, err := s.repo.FindTasksWithDetails(project.ID)
+		if err != nil {
+			return nil, err
+		}
+		labelNames, err := s.repo.FindTaskLabelNames(project.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Dates become offsets from the day the project was created
+		for _, t := range projectTasks {
+			template.Tasks = append(template.Tasks, TemplateTask{
+				Title:           t.Title,
+				Description:     t.Description,
+				StatusName:      t.Status.Name,
+				PriorityName:    t.Priority.Name,
+				Labels:          labelNames[t.ID],
+				StartOffsetDays: dayOffset(project.CreatedAt, t.StartDate),
+				EndOffsetDays:   dayOffset(project.CreatedAt, t.EndDate),
+				EstimateMinutes: t.EstimateMinutes,
+			})
+		}
+	}
+
+	return s.CreateTemplate(template, userID)
+}
+
+func (s *projectService) DeleteTemplate(id string, orgID string, userID uint) error {
+	template, err := s.repo.FindTemplateByIDAndOrg(id, orgID)
+	if err != nil {
+		return ErrTemplateNotFound
+	}
+
+	// Only the author or an org admin can remove a shared template
+	if template.CreatedBy != userID {
+		isAdmin, err := s.repo.IsOrgAdmin(template.OrganizationID, userID)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return ErrProjectForbidden
+		}
+	}
+
+	return s.repo.DeleteTemplate(template)
+}
+
+// CloneProject copies a project the caller contributes to; viewers cannot clone
+func (s *projectService) CloneProject(id string, orgID string, userID uint, input CloneProjectInput) (*Project, error) {
+	source, _, err := s.AuthorizeProject(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.resolveKey(source.OrganizationID, "", input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	target := Project{
+		Key:            key,
+		Name:           input.Name,
+		Description:    source.Description,
+		Visibility:     source.Visibility,
+		OrganizationID: source.OrganizationID,
+	}
+
+	if err := s.repo.CloneProject(source, &target, userID, input); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+func (s *projectService) MoveProject(id string, orgID string, userID uint, input MoveProjectInput) (*MoveProjectResult, error) {
+	project, err := s.repo.FindByIDAndOrg(id, orgID)
+	if err != nil {
+		return nil, ErrProjectNotFound
+	}
+	if project.OrganizationID == input.DestinationOrgID {
+		return nil, errors.New("project already belongs to this organization")
+	}
+	if input.AssigneeAction != "" && input.AssigneeAction != MoveActionUnassign && input.AssigneeAction != MoveActionInvite {
+		return nil, errors.New("assignee_action must be 'unassign' or 'invite'")
+	}
+
+	// 1. Security: admin on both sides
+	for _, org := range []uint{project.OrganizationID, input.DestinationOrgID} {
+		isAdmin, err := s.repo.IsOrgAdmin(org, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isAdmin {
+			return nil, ErrOrgAdminRequired
+		}
+	}
+
+	// 2. Who would lose access after the move
+	conflicts, err := s.repo.FindUsersOutsideOrg(project.ID, input.DestinationOrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Keys are unique per org, keep the old one unless it clashes
+	key, err := s.freeKey(input.DestinationOrgID, project.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MoveProjectResult{
+		Project:        project,
+		Conflicts:      conflicts,
+		AssigneeAction: input.AssigneeAction,
+		KeyChanged:     key != project.Key,
+		DryRun:         input.DryRun,
+	}
+	if input.DryRun {
+		return result, nil
+	}
+	if len(conflicts) > 0 && input.AssigneeAction == "" {
+		return result, ErrMoveConflicts
+	}
+
+	var userIDs []uint
+	for _, c := range conflicts {
+		userIDs = append(userIDs, c.UserID)
+	}
+
+	if err := s.repo.MoveProject(project, input.DestinationOrgID, key, input.AssigneeAction, userIDs); err != nil {
+		return nil, err
+	}
+
+	moved, err := s.repo.FindByIDAndOrg(id, interfaceToString(input.DestinationOrgID))
+	if err != nil {
+		return nil, err
+	}
+	result.Project = moved
+	result.KeyChanged = moved.Key != project.Key
+	return result, nil
+}
+
+// Helper: validates a requested key, or derives a free one from the project name. The
+// repository settles the key again while the org is locked, as it may be taken meanwhile.
+func (s *projectService) resolveKey(orgID uint, requested string, name string) (string, error) {
+	if requested != "" {
+		key := strings.ToUpper(requested)
+		if !projectKeyPattern.MatchString(key) {
+			return "", ErrInvalidKey
+		}
+		taken, err := s.repo.KeyExists(orgID, key)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", ErrKeyTaken
+		}
+		return key, nil
+	}
+
+	return s.freeKey(orgID, SuggestKey(name))
+}
+
+// Helper: returns base, or base with the first numeric suffix not yet used in the org
+func (s *projectService) freeKey(orgID uint, base string) (string, error) {
+	return FreeKey(orgID, base, s.repo.KeyExists)
+}
+
+// FreeKey returns base, or base with the first numeric suffix ("WEB2", "WEB3", ...)
+// that exists reports as unused in the org. Keys stay at most 10 characters.
+func FreeKey(orgID uint, base string, exists func(orgID uint, key string) (bool, error)) (string, error) {
+	for i := 1; ; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := strconv.Itoa(i)
+			if len(base)+len(suffix) > 10 {
+				candidate = base[:10-len(suffix)]
+			}
+			candidate += suffix
+		}
+
+		taken, err := exists(orgID, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+}
+
+// SuggestKey derives a project key from a name: initials for multi-word names
+// ("Web Platform" -> "WP"), otherwise the first letters ("Website" -> "WEBS").
+// Only A-Z and 0-9 are kept; a name without any falls back to "PRJ".
+func SuggestKey(name string) string {
+	words := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9')
+	})
+
+	var key []rune
+	if len(words) > 1 {
+		for _, w := range words {
+			key = append(key, []rune(w)[0])
+		}
+	} else if len(words) == 1 {
+		key = []rune(words[0])
+	}
+
+	// Keys must start with a letter
+	for len(key) > 0 && key[0] >= '0' && key[0] <= '9' {
+		key = key[1:]
+	}
+	if len(key) > 4 {
+		key = key[:4]
+	}
+	if len(key) == 0 {
+		return "PRJ"
+	}
+	if len(key) < 2 {
+		return string(key) + "PR"
+	}
+	return string(key)
+}
+
+// Helper: template sanity checks shared by create and save-as
+func validateTemplate(template *ProjectTemplate) error {
+	if template.Name == "" {
+		return errors.New("template name is required")
+	}
+	if len(template.Statuses) == 0 {
+		return errors.New("template needs at least one status")
+	}
+
+	statusNames := make(map[string]bool)
+	for _, st := range template.Statuses {
+		if st.Name == "" {
+			return errors.New("template status name cannot be empty")
+		}
+		if statusNames[st.Name] {
+			return errors.New("template status names must be unique")
+		}
+		if st.Category != "" && !tasks.IsValidStatusCategory(st.Category) {
+			return tasks.ErrInvalidCategory
+		}
+		statusNames[st.Name] = true
+	}
+
+	labelNames := make(map[string]bool)
+	for _, l := range template.Labels {
+		if err := tasks.ValidateLabel(l.Name, l.Color); err != nil {
+			return err
+		}
+		if labelNames[strings.ToLower(l.Name)] {
+			return errors.New("template label names must be unique")
+		}
+		labelNames[strings.ToLower(l.Name)] = true
+	}
+
+	fieldNames := make(map[string]bool)
+	for _, f := range template.CustomFields {
+		if err := tasks.ValidateCustomFieldDefinition(f.Name, f.Type, f.Options); err != nil {
+			return err
+		}
+		if fieldNames[strings.ToLower(f.Name)] {
+			return errors.New("template custom field names must be unique")
+		}
+		fieldNames[strings.ToLower(f.Name)] = true
+	}
+
+	for _, t := range template.Tasks {
+		if t.StatusName != "" && !statusNames[t.StatusName] {
+			return errors.New("template task '" + t.Title + "' uses unknown status '" + t.StatusName + "'")
+		}
+		if utf8.RuneCountInString(t.Description) > tasks.MaxDescriptionLength {
+			return errors.New("template task '" + t.Title + "': " + tasks.ErrDescriptionTooLong.Error())
+		}
+		if t.EstimateMinutes != nil && (*t.EstimateMinutes < 0 || *t.EstimateMinutes > tasks.MaxEstimateMinutes) {
+			return errors.New("template task '" + t.Title + "': " + tasks.ErrInvalidEstimate.Error())
+		}
+	}
+	return nil
+}
+
+// Helper: whole days between the project start and a task date
+func dayOffset(start time.Time, date *time.Time) *int {
+	if date == nil {
+		return nil
+	}
+	days := int(date.Sub(start).Hours() / 24)
+	return &days
+}
 
-	// 3. Delete
-	return s.repo.Delete(project)
+// Helper function to convert uint ID to string
+func interfaceToString(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
 }
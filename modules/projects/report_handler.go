@@ -0,0 +1,95 @@
+package projects
+
+import (
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	service ReportService
+}
+
+func NewReportHandler(service ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// GET /projects/:id/stats?weeks=8
+func (h *ReportHandler) GetProjectStats(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	weeks, err := strconv.Atoi(c.DefaultQuery("weeks", "8"))
+	if err != nil || weeks < 1 || weeks > 52 {
+		utils.SendError(c, http.StatusBadRequest, "weeks must be a number between 1 and 52")
+		return
+	}
+
+	stats, err := h.service.GetProjectStats(projectID, orgID, user.ID, weeks)
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", stats)
+}
+
+// GET /projects/:id/reports/cfd?from=2024-01-01&to=2024-01-31 (defaults to the last 30 days)
+func (h *ReportHandler) GetCumulativeFlow(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	to := time.Now()
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse(dateLayout, v)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "to must be a date like 2024-01-31")
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -29)
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse(dateLayout, v)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "from must be a date like 2024-01-01")
+			return
+		}
+		from = parsed
+	}
+
+	flow, err := h.service.GetCumulativeFlow(projectID, orgID, user.ID, from, to)
+	if err != nil {
+		sendProjectError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", flow)
+}
+
+// GET /projects/:id/reports/burndown?sprint=12
+func (h *ReportHandler) GetBurndown(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	sprintID := c.Query("sprint")
+	if _, err := strconv.ParseUint(sprintID, 10, 64); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "sprint query parameter is required")
+		return
+	}
+
+	burndown, err := h.service.GetBurndown(projectID, sprintID, orgID, user.ID)
+	if err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", burndown)
+}
@@ -0,0 +1,94 @@
+package projects
+
+import "time"
+
+// PriorityTaskCount is one row of the per-priority breakdown of a project
+type PriorityTaskCount struct {
+	PriorityID uint   `json:"priority_id"`
+	Name       string `json:"name"`
+	Level      int    `json:"level"`
+	Color      string `json:"color"`
+	TaskCount  int64  `json:"task_count"`
+}
+
+// AssigneeTaskCount is the workload of one assignee in a project
+type AssigneeTaskCount struct {
+	UserID       uint   `json:"user_id"`
+	Email        string `json:"email"`
+	TaskCount    int64  `json:"task_count"`
+	OpenCount    int64  `json:"open_count"`
+	OverdueCount int64  `json:"overdue_count"`
+}
+
+// WeeklyThroughput counts tasks created and completed in the week starting at WeekStart
+type WeeklyThroughput struct {
+	WeekStart time.Time `json:"week_start"`
+	Created   int64     `json:"created"`
+	Completed int64     `json:"completed"`
+}
+
+// TaskSummary holds the project-wide counters of the stats endpoint
+type TaskSummary struct {
+	TotalTasks      int64 `json:"total_tasks"`
+	OpenTasks       int64 `json:"open_tasks"`
+	DoneTasks       int64 `json:"done_tasks"`
+	OverdueTasks    int64 `json:"overdue_tasks"` // end_date in the past and not in a "done" status
+	UnassignedTasks int64 `json:"unassigned_tasks"`
+}
+
+// ProjectStats is the response for GET /projects/:id/stats
+type ProjectStats struct {
+	ProjectID uint `json:"project_id"`
+	TaskSummary
+	ByStatus   []StatusTaskCount   `json:"by_status"`
+	ByPriority []PriorityTaskCount `json:"by_priority"`
+	ByAssignee []AssigneeTaskCount `json:"by_assignee"`
+	Weekly     []WeeklyThroughput  `json:"weekly"`
+}
+
+// StatusSnapshot is the number of tasks in a status at the end of a day, recorded by the snapshot job
+type StatusSnapshot struct {
+	ProjectID uint      `gorm:"primaryKey" json:"project_id"`
+	StatusID  uint      `gorm:"primaryKey" json:"status_id"`
+	Date      time.Time `gorm:"primaryKey;type:date" json:"date"`
+	TaskCount int64     `json:"task_count"`
+}
+
+// SprintSnapshot is the scope and the work left in an active sprint on a given day
+type SprintSnapshot struct {
+	SprintID       uint      `gorm:"primaryKey" json:"sprint_id"`
+	Date           time.Time `gorm:"primaryKey;type:date" json:"date"`
+	TotalTasks     int64     `json:"total_tasks"`
+	RemainingTasks int64     `json:"remaining_tasks"`
+}
+
+// CFDSeries is one band of the cumulative flow chart, aligned with CumulativeFlow.Dates
+type CFDSeries struct {
+	StatusID uint    `json:"status_id"`
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+	Index    int     `json:"index"`
+	Values   []int64 `json:"values"`
+}
+
+// CumulativeFlow is the response for GET /projects/:id/reports/cfd
+type CumulativeFlow struct {
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Dates  []string    `json:"dates"`
+	Series []CFDSeries `json:"series"`
+}
+
+// BurndownPoint is one day of a sprint; Total and Remaining stay null for days still ahead
+type BurndownPoint struct {
+	Date      string  `json:"date"`
+	Total     *int64  `json:"total"`
+	Remaining *int64  `json:"remaining"`
+	Ideal     float64 `json:"ideal"`
+}
+
+// Burndown is the response for GET /projects/:id/reports/burndown
+type Burndown struct {
+	Sprint Sprint          `json:"sprint"`
+	Points []BurndownPoint `json:"points"`
+}
@@ -0,0 +1,178 @@
+package projects
+
+import (
+	"gotask-backend/modules/tasks"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	TaskSummary(projectID uint) (*TaskSummary, error)
+	TasksPerStatus(projectID uint) ([]StatusTaskCount, error)
+	TasksPerPriority(projectID uint) ([]PriorityTaskCount, error)
+	TasksPerAssignee(projectID uint) ([]AssigneeTaskCount, error)
+	WeeklyThroughput(projectID uint, weeks int) ([]WeeklyThroughput, error)
+
+	RecordSnapshots() error
+	FindStatusSnapshots(projectID uint, from time.Time, to time.Time) ([]StatusSnapshot, error)
+	FindSprintSnapshots(sprintID uint) ([]SprintSnapshot, error)
+	FindSprint(projectID uint, sprintID string) (*Sprint, error)
+	CountCommittedTasks(sprintID uint) (int64, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db}
+}
+
+func (r *reportRepository) TaskSummary(projectID uint) (*TaskSummary, error) {
+	var summary TaskSummary
+	err := r.db.Model(&tasks.Task{}).
+		Select(`COUNT(tasks.id) AS total_tasks,
+			COUNT(tasks.id) FILTER (WHERE statuses.category <> ?) AS open_tasks,
+			COUNT(tasks.id) FILTER (WHERE statuses.category = ?) AS done_tasks,
+			COUNT(tasks.id) FILTER (WHERE statuses.category <> ? AND tasks.end_date < NOW()) AS overdue_tasks,
+			COUNT(tasks.id) FILTER (WHERE NOT EXISTS (SELECT 1 FROM task_users WHERE task_users.task_id = tasks.id)) AS unassigned_tasks`,
+			tasks.StatusCategoryDone, tasks.StatusCategoryDone, tasks.StatusCategoryDone).
+		Joins("JOIN statuses ON statuses.id = tasks.status_id").
+		Where("tasks.project_id = ?", projectID).
+		Scan(&summary).Error
+	return &summary, err
+}
+
+func (r *reportRepository) TasksPerStatus(projectID uint) ([]StatusTaskCount, error) {
+	var counts []StatusTaskCount
+	err := r.db.Model(&tasks.Status{}).
+		Select("statuses.id AS status_id, statuses.name, statuses.index, statuses.category, COUNT(tasks.id) AS task_count").
+		Joins("LEFT JOIN tasks ON tasks.status_id = statuses.id AND tasks.project_id = statuses.project_id AND tasks.deleted_at IS NULL").
+		Where("statuses.project_id = ?", projectID).
+		Group("statuses.id, statuses.name, statuses.index, statuses.category").
+		Order("statuses.index asc").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// TasksPerPriority lists every priority, including the ones no task uses
+func (r *reportRepository) TasksPerPriority(projectID uint) ([]PriorityTaskCount, error) {
+	var counts []PriorityTaskCount
+	err := r.db.Model(&tasks.Priority{}).
+		Select("priorities.id AS priority_id, priorities.name, priorities.level, priorities.color, COUNT(tasks.id) AS task_count").
+		Joins("LEFT JOIN tasks ON tasks.priority_id = priorities.id AND tasks.project_id = ? AND tasks.deleted_at IS NULL", projectID).
+		Group("priorities.id, priorities.name, priorities.level, priorities.color").
+		Order("priorities.level asc").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *reportRepository) TasksPerAssignee(projectID uint) ([]AssigneeTaskCount, error) {
+	var counts []AssigneeTaskCount
+	err := r.db.Table("task_users").
+		Select(`users.id AS user_id, users.email,
+			COUNT(tasks.id) AS task_count,
+			COUNT(tasks.id) FILTER (WHERE statuses.category <> ?) AS open_count,
+			COUNT(tasks.id) FILTER (WHERE statuses.category <> ? AND tasks.end_date < NOW()) AS overdue_count`,
+			tasks.StatusCategoryDone, tasks.StatusCategoryDone).
+		Joins("JOIN tasks ON tasks.id = task_users.task_id AND tasks.deleted_at IS NULL").
+		Joins("JOIN statuses ON statuses.id = tasks.status_id").
+		Joins("JOIN users ON users.id = task_users.user_id").
+		Where("tasks.project_id = ?", projectID).
+		Group("users.id, users.email").
+		Order("task_count desc, users.email asc").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// WeeklyThroughput returns one row per week for the last `weeks` weeks (current week included),
+// weeks without activity show up as zeros
+func (r *reportRepository) WeeklyThroughput(projectID uint, weeks int) ([]WeeklyThroughput, error) {
+	var rows []WeeklyThroughput
+	err := r.db.Raw(`
+		SELECT weeks.week_start,
+			(SELECT COUNT(*) FROM tasks
+				WHERE tasks.project_id = @project AND tasks.deleted_at IS NULL
+				AND tasks.created_at >= weeks.week_start AND tasks.created_at < weeks.week_start + INTERVAL '1 week') AS created,
+			(SELECT COUNT(*) FROM tasks
+				WHERE tasks.project_id = @project AND tasks.deleted_at IS NULL
+				AND tasks.completed_at >= weeks.week_start AND tasks.completed_at < weeks.week_start + INTERVAL '1 week') AS completed
+		FROM generate_series(
+			date_trunc('week', NOW()) - (@weeks - 1) * INTERVAL '1 week',
+			date_trunc('week', NOW()),
+			INTERVAL '1 week'
+		) AS weeks(week_start)
+		ORDER BY weeks.week_start asc`,
+		map[string]interface{}{"project": projectID, "weeks": weeks}).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// RecordSnapshots stores today's task count per status for every live project and the
+// burndown numbers of every active sprint. Running it again the same day overwrites the rows.
+func (r *reportRepository) RecordSnapshots() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO status_snapshots (project_id, status_id, date, task_count)
+			SELECT statuses.project_id, statuses.id, CURRENT_DATE, COUNT(tasks.id)
+			FROM statuses
+			JOIN projects ON projects.id = statuses.project_id
+				AND projects.deleted_at IS NULL AND projects.archived_at IS NULL
+			LEFT JOIN tasks ON tasks.status_id = statuses.id AND tasks.deleted_at IS NULL
+			GROUP BY statuses.project_id, statuses.id
+			ON CONFLICT (project_id, status_id, date) DO UPDATE SET task_count = EXCLUDED.task_count`).Error; err != nil {
+			return err
+		}
+		return recordSprintSnapshots(tx, "sprints.state = ?", SprintStateActive)
+	})
+}
+
+// Helper: upserts today's burndown row for the sprints matching the condition
+func recordSprintSnapshots(tx *gorm.DB, condition string, args ...interface{}) error {
+	values := append([]interface{}{tasks.StatusCategoryDone}, args...)
+	return tx.Exec(`
+		INSERT INTO sprint_snapshots (sprint_id, date, total_tasks, remaining_tasks)
+		SELECT sprints.id, CURRENT_DATE, COUNT(tasks.id), COUNT(tasks.id) FILTER (WHERE statuses.category <> ?)
+		FROM sprints
+		LEFT JOIN tasks ON tasks.sprint_id = sprints.id AND tasks.deleted_at IS NULL
+		LEFT JOIN statuses ON statuses.id = tasks.status_id
+		WHERE `+condition+`
+		GROUP BY sprints.id
+		ON CONFLICT (sprint_id, date) DO UPDATE
+			SET total_tasks = EXCLUDED.total_tasks, remaining_tasks = EXCLUDED.remaining_tasks`,
+		values...).Error
+}
+
+func (r *reportRepository) FindStatusSnapshots(projectID uint, from time.Time, to time.Time) ([]StatusSnapshot, error) {
+	var snapshots []StatusSnapshot
+	err := r.db.Where("project_id = ? AND date BETWEEN ? AND ?", projectID, from, to).
+		Order("date asc").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+func (r *reportRepository) FindSprintSnapshots(sprintID uint) ([]SprintSnapshot, error) {
+	var snapshots []SprintSnapshot
+	err := r.db.Where("sprint_id = ?", sprintID).
+		Order("date asc").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+func (r *reportRepository) FindSprint(projectID uint, sprintID string) (*Sprint, error) {
+	var sprint Sprint
+	err := r.db.Where("id = ? AND project_id = ?", sprintID, projectID).First(&sprint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (r *reportRepository) CountCommittedTasks(sprintID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&SprintTask{}).
+		Where("sprint_id = ? AND committed = ?", sprintID, true).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,209 @@
+package projects
+
+import (
+	"errors"
+	"gotask-backend/models"
+	"time"
+)
+
+type ReportService interface {
+	GetProjectStats(projectID string, orgID string, userID uint, weeks int) (*ProjectStats, error)
+	GetCumulativeFlow(projectID string, orgID string, userID uint, from time.Time, to time.Time) (*CumulativeFlow, error)
+	GetBurndown(projectID string, sprintID string, orgID string, userID uint) (*Burndown, error)
+	RecordSnapshots() error
+}
+
+var ErrInvalidRange = errors.New("'from' must not be after 'to' and the range is limited to 366 days")
+
+const dateLayout = "2006-01-02"
+
+type reportService struct {
+	repo           ReportRepository
+	projectService ProjectService
+}
+
+func NewReportService(repo ReportRepository, projectService ProjectService) ReportService {
+	return &reportService{repo, projectService}
+}
+
+func (s *reportService) GetProjectStats(projectID string, orgID string, userID uint, weeks int) (*ProjectStats, error) {
+	project, _, err := s.projectService.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.repo.TaskSummary(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	byStatus, err := s.repo.TasksPerStatus(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	byPriority, err := s.repo.TasksPerPriority(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	byAssignee, err := s.repo.TasksPerAssignee(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	weekly, err := s.repo.WeeklyThroughput(project.ID, weeks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectStats{
+		ProjectID:   project.ID,
+		TaskSummary: *summary,
+		ByStatus:    byStatus,
+		ByPriority:  byPriority,
+		ByAssignee:  byAssignee,
+		Weekly:      weekly,
+	}, nil
+}
+
+// RecordSnapshots is run by the daily snapshot job
+func (s *reportService) RecordSnapshots() error {
+	return s.repo.RecordSnapshots()
+}
+
+// GetCumulativeFlow builds one series per status over [from, to]. Days without a snapshot
+// repeat the previous day, and today always uses live counts.
+func (s *reportService) GetCumulativeFlow(projectID string, orgID string, userID uint, from time.Time, to time.Time) (*CumulativeFlow, error) {
+	from, to = dayOf(from), dayOf(to)
+	if from.After(to) || to.Sub(from) > 366*24*time.Hour {
+		return nil, ErrInvalidRange
+	}
+
+	project, _, err := s.projectService.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := s.repo.TasksPerStatus(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := s.repo.FindStatusSnapshots(project.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// counts[statusID][date]
+	counts := make(map[uint]map[string]int64)
+	for _, snap := range snapshots {
+		if counts[snap.StatusID] == nil {
+			counts[snap.StatusID] = make(map[string]int64)
+		}
+		counts[snap.StatusID][snap.Date.Format(dateLayout)] = snap.TaskCount
+	}
+
+	today := dayOf(time.Now()).Format(dateLayout)
+	flow := CumulativeFlow{
+		From:   from.Format(dateLayout),
+		To:     to.Format(dateLayout),
+		Dates:  []string{},
+		Series: make([]CFDSeries, 0, len(statuses)),
+	}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		flow.Dates = append(flow.Dates, d.Format(dateLayout))
+	}
+
+	for _, status := range statuses {
+		series := CFDSeries{
+			StatusID: status.StatusID,
+			Name:     status.Name,
+			Category: status.Category,
+			Index:    status.Index,
+			Values:   make([]int64, 0, len(flow.Dates)),
+		}
+
+		var last int64
+		for _, date := range flow.Dates {
+			if date == today {
+				last = status.TaskCount
+			} else if count, ok := counts[status.StatusID][date]; ok {
+				last = count
+			}
+			series.Values = append(series.Values, last)
+		}
+		flow.Series = append(flow.Series, series)
+	}
+
+	return &flow, nil
+}
+
+// GetBurndown walks the sprint day by day. The ideal line goes from the scope on the
+// first recorded day (or the committed tasks) down to zero on the end date.
+func (s *reportService) GetBurndown(projectID string, sprintID string, orgID string, userID uint) (*Burndown, error) {
+	project, _, err := s.projectService.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint, err := s.repo.FindSprint(project.ID, sprintID)
+	if err != nil {
+		return nil, ErrSprintNotFound
+	}
+
+	snapshots, err := s.repo.FindSprintSnapshots(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]SprintSnapshot, len(snapshots))
+	for _, snap := range snapshots {
+		byDate[snap.Date.Format(dateLayout)] = snap
+	}
+
+	start, end := dayOf(sprint.StartDate), dayOf(sprint.EndDate)
+	// Sprints saved before MaxSprintDays existed are charted over that many days at most
+	if limit := start.AddDate(0, 0, MaxSprintDays); end.After(limit) {
+		end = limit
+	}
+	lastKnown := dayOf(time.Now())
+	if sprint.ClosedAt != nil && sprint.ClosedAt.Before(lastKnown) {
+		lastKnown = dayOf(*sprint.ClosedAt)
+	}
+
+	burndown := Burndown{Sprint: *sprint, Points: []BurndownPoint{}}
+	var current *SprintSnapshot
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		point := BurndownPoint{Date: d.Format(dateLayout)}
+		if snap, ok := byDate[point.Date]; ok {
+			current = &snap
+		}
+		if current != nil && !d.After(lastKnown) {
+			total, remaining := current.TotalTasks, current.RemainingTasks
+			point.Total, point.Remaining = &total, &remaining
+		}
+		burndown.Points = append(burndown.Points, point)
+	}
+
+	var scope float64
+	if len(snapshots) > 0 {
+		scope = float64(snapshots[0].TotalTasks)
+	} else {
+		committed, err := s.repo.CountCommittedTasks(sprint.ID)
+		if err != nil {
+			return nil, err
+		}
+		scope = float64(committed)
+	}
+
+	days := len(burndown.Points) - 1
+	for i := range burndown.Points {
+		if days == 0 {
+			burndown.Points[i].Ideal = 0
+			continue
+		}
+		burndown.Points[i].Ideal = scope * float64(days-i) / float64(days)
+	}
+
+	return &burndown, nil
+}
+
+// Helper: midnight UTC of the calendar day t falls on
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
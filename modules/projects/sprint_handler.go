@@ -0,0 +1,236 @@
+package projects
+
+import (
+	"errors"
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SprintHandler struct {
+	service SprintService
+}
+
+func NewSprintHandler(service SprintService) *SprintHandler {
+	return &SprintHandler{service: service}
+}
+
+// GET /projects/:id/sprints
+func (h *SprintHandler) FindSprints(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	sprints, err := h.service.GetSprints(projectID, orgID, user.ID)
+	if err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", sprints)
+}
+
+// GET /sprints/:id
+func (h *SprintHandler) GetSprint(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	sprint, err := h.service.GetSprint(id, orgID, user.ID)
+	if err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", sprint)
+}
+
+// POST /projects/:id/sprints
+func (h *SprintHandler) CreateSprint(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name      string    `json:"name" binding:"required"`
+		Goal      string    `json:"goal"`
+		StartDate time.Time `json:"start_date" binding:"required"`
+		EndDate   time.Time `json:"end_date" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sprint, err := h.service.CreateSprint(projectID, orgID, user.ID, SprintInput{
+		Name:      req.Name,
+		Goal:      req.Goal,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	})
+	if err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Sprint created successfully", sprint)
+}
+
+// PATCH /sprints/:id
+func (h *SprintHandler) UpdateSprint(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name      *string    `json:"name"`
+		Goal      *string    `json:"goal"`
+		StartDate *time.Time `json:"start_date"`
+		EndDate   *time.Time `json:"end_date"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sprint, err := h.service.UpdateSprint(id, orgID, user.ID, UpdateSprintInput{
+		Name:      req.Name,
+		Goal:      req.Goal,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	})
+	if err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Sprint updated successfully", sprint)
+}
+
+// DELETE /sprints/:id
+func (h *SprintHandler) DeleteSprint(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteSprint(id, orgID, user.ID); err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Sprint deleted successfully")
+}
+
+// POST /sprints/:id/tasks
+func (h *SprintHandler) AddTasks(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		TaskIDs []uint `json:"task_ids" binding:"required,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.AddTasks(id, orgID, user.ID, req.TaskIDs); err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Tasks added to sprint")
+}
+
+// DELETE /sprints/:id/tasks/:taskId
+func (h *SprintHandler) RemoveTask(c *gin.Context) {
+	id := c.Param("id")
+	taskID := c.Param("taskId")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.RemoveTask(id, taskID, orgID, user.ID); err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Task moved back to the backlog")
+}
+
+// POST /sprints/:id/start
+func (h *SprintHandler) StartSprint(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	sprint, err := h.service.StartSprint(id, orgID, user.ID)
+	if err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Sprint started", sprint)
+}
+
+// POST /sprints/:id/close
+// Body (optional): {"next_sprint_id": 12}; without it unfinished tasks go back to the backlog
+func (h *SprintHandler) CloseSprint(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		NextSprintID *uint `json:"next_sprint_id"`
+	}
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	report, err := h.service.CloseSprint(id, orgID, user.ID, req.NextSprintID)
+	if err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Sprint closed", report)
+}
+
+// GET /sprints/:id/report
+func (h *SprintHandler) GetReport(c *gin.Context) {
+	id := c.Param("id")
+	orgID := c.MustGet("org_id").(string)
+	user := c.MustGet("user").(auth.User)
+
+	report, err := h.service.GetReport(id, orgID, user.ID)
+	if err != nil {
+		sendSprintError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Success", report)
+}
+
+// Helper: same mapping as projects, plus sprint lookups and state conflicts
+func sendSprintError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrSprintNotFound):
+		utils.SendError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrSprintClosed), errors.Is(err, ErrSprintNotPlanned),
+		errors.Is(err, ErrSprintNotActive), errors.Is(err, ErrSprintActive),
+		errors.Is(err, ErrActiveSprintExists):
+		utils.SendError(c, http.StatusConflict, err.Error())
+	default:
+		sendProjectError(c, err)
+	}
+}
@@ -0,0 +1,66 @@
+package projects
+
+import "time"
+
+// Sprint states
+const (
+	SprintStatePlanned = "planned"
+	SprintStateActive  = "active"
+	SprintStateClosed  = "closed"
+)
+
+// MaxSprintDays is the longest a sprint may run; burndowns have one point per day
+const MaxSprintDays = 90
+
+type Sprint struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	ProjectID uint       `gorm:"index;uniqueIndex:idx_sprints_one_active,where:state = 'active'" json:"project_id"`
+	Name      string     `json:"name"`
+	Goal      string     `json:"goal"`
+	StartDate time.Time  `json:"start_date"`
+	EndDate   time.Time  `json:"end_date"`
+	State     string     `gorm:"default:planned" json:"state"`
+	StartedAt *time.Time `json:"started_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SprintTask records a task's part in a sprint: committed when the sprint
+// started, and how it ended up once the sprint was closed
+type SprintTask struct {
+	SprintID    uint `gorm:"primaryKey" json:"sprint_id"`
+	TaskID      uint `gorm:"primaryKey" json:"task_id"`
+	Committed   bool `json:"committed"`
+	Completed   bool `json:"completed"`
+	CarriedOver bool `json:"carried_over"`
+}
+
+// SprintTaskRow is a task of the sprint (or of its snapshot) with its status category
+type SprintTaskRow struct {
+	TaskID   uint   `json:"task_id"`
+	Number   uint   `json:"-"`
+	Key      string `gorm:"-" json:"key"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Category string `json:"category"`
+	SprintID *uint  `json:"-"`
+
+	Committed   bool `json:"committed"`
+	Added       bool `json:"added"`   // joined after the sprint started
+	Removed     bool `json:"removed"` // committed, then taken out of the sprint
+	Completed   bool `json:"completed"`
+	CarriedOver bool `json:"carried_over"`
+}
+
+// SprintReport compares what the team committed to with what got done
+type SprintReport struct {
+	Sprint
+	Committed          int             `json:"committed"`
+	CommittedCompleted int             `json:"committed_completed"`
+	Added              int             `json:"added"`
+	Removed            int             `json:"removed"`
+	Completed          int             `json:"completed"`
+	CarriedOver        int             `json:"carried_over"`
+	CompletionRate     float64         `json:"completion_rate"` // committed_completed / committed
+	Tasks              []SprintTaskRow `json:"tasks"`
+}
@@ -0,0 +1,204 @@
+package projects
+
+import (
+	"gotask-backend/modules/tasks"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SprintRepository interface {
+	Create(sprint *Sprint) error
+	FindByProject(projectID uint) ([]Sprint, error)
+	FindByID(id string) (*Sprint, error)
+	Update(sprint *Sprint, updates map[string]interface{}) error
+	Delete(sprint *Sprint) error
+	HasActiveSprint(projectID uint) (bool, error)
+
+	CountTasksInProject(projectID uint, taskIDs []uint) (int64, error)
+	SetTasksSprint(projectID uint, taskIDs []uint, sprintID *uint) error
+	RemoveTask(sprintID uint, taskID string) (bool, error)
+	FindTaskRows(sprint *Sprint) ([]SprintTaskRow, error)
+
+	Start(sprint *Sprint, startedAt time.Time) error
+	Close(sprint *Sprint, closedAt time.Time, nextSprintID *uint) error
+}
+
+type sprintRepository struct {
+	db *gorm.DB
+}
+
+func NewSprintRepository(db *gorm.DB) SprintRepository {
+	return &sprintRepository{db}
+}
+
+func (r *sprintRepository) Create(sprint *Sprint) error {
+	return r.db.Create(sprint).Error
+}
+
+func (r *sprintRepository) FindByProject(projectID uint) ([]Sprint, error) {
+	var sprints []Sprint
+	err := r.db.Where("project_id = ?", projectID).
+		Order("start_date asc, id asc").
+		Find(&sprints).Error
+	return sprints, err
+}
+
+func (r *sprintRepository) FindByID(id string) (*Sprint, error) {
+	var sprint Sprint
+	err := r.db.Where("id = ?", id).First(&sprint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (r *sprintRepository) Update(sprint *Sprint, updates map[string]interface{}) error {
+	return r.db.Model(sprint).Updates(updates).Error
+}
+
+// Delete sends the sprint's tasks (trashed ones included) back to the backlog before removing it
+func (r *sprintRepository) Delete(sprint *Sprint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&tasks.Task{}).
+			Where("sprint_id = ?", sprint.ID).
+			Update("sprint_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("sprint_id = ?", sprint.ID).Delete(&SprintTask{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("sprint_id = ?", sprint.ID).Delete(&SprintSnapshot{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(sprint).Error
+	})
+}
+
+func (r *sprintRepository) HasActiveSprint(projectID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&Sprint{}).
+		Where("project_id = ? AND state = ?", projectID, SprintStateActive).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *sprintRepository) CountTasksInProject(projectID uint, taskIDs []uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&tasks.Task{}).
+		Where("project_id = ? AND id IN ?", projectID, taskIDs).
+		Count(&count).Error
+	return count, err
+}
+
+// SetTasksSprint moves tasks into a sprint, or back to the backlog when sprintID is nil
+func (r *sprintRepository) SetTasksSprint(projectID uint, taskIDs []uint, sprintID *uint) error {
+	return r.db.Model(&tasks.Task{}).
+		Where("project_id = ? AND id IN ?", projectID, taskIDs).
+		Update("sprint_id", sprintID).Error
+}
+
+// RemoveTask sends a task of the sprint back to the backlog
+func (r *sprintRepository) RemoveTask(sprintID uint, taskID string) (bool, error) {
+	result := r.db.Model(&tasks.Task{}).
+		Where("id = ? AND sprint_id = ?", taskID, sprintID).
+		Update("sprint_id", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindTaskRows lists the tasks currently in the sprint plus the ones from its
+// snapshot that have left it, along with whatever the snapshot recorded
+func (r *sprintRepository) FindTaskRows(sprint *Sprint) ([]SprintTaskRow, error) {
+	var rows []SprintTaskRow
+	err := r.db.Table("tasks").
+		Select(`tasks.id AS task_id, tasks.number, tasks.title, tasks.sprint_id,
+			statuses.name AS status, statuses.category,
+			COALESCE(sprint_tasks.committed, false) AS committed,
+			COALESCE(sprint_tasks.completed, false) AS completed,
+			COALESCE(sprint_tasks.carried_over, false) AS carried_over`).
+		Joins("JOIN statuses ON statuses.id = tasks.status_id").
+		Joins("LEFT JOIN sprint_tasks ON sprint_tasks.task_id = tasks.id AND sprint_tasks.sprint_id = ?", sprint.ID).
+		Where("tasks.deleted_at IS NULL").
+		Where("tasks.sprint_id = ? OR sprint_tasks.sprint_id IS NOT NULL", sprint.ID).
+		Order("tasks.number asc").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Start snapshots the tasks in the sprint as the team's commitment and activates it
+func (r *sprintRepository) Start(sprint *Sprint, startedAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`INSERT INTO sprint_tasks (sprint_id, task_id, committed, completed, carried_over)
+			SELECT ?, id, true, false, false FROM tasks
+			WHERE sprint_id = ? AND deleted_at IS NULL
+			ON CONFLICT DO NOTHING`, sprint.ID, sprint.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(sprint).Updates(map[string]interface{}{
+			"state":      SprintStateActive,
+			"started_at": startedAt,
+		}).Error
+	})
+}
+
+// Close records how every task in the sprint ended up, then moves the unfinished
+// ones to nextSprintID (nil = backlog) and closes the sprint
+func (r *sprintRepository) Close(sprint *Sprint, closedAt time.Time, nextSprintID *uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current []struct {
+			ID       uint
+			Category string
+		}
+		if err := tx.Model(&tasks.Task{}).
+			Select("tasks.id, statuses.category").
+			Joins("JOIN statuses ON statuses.id = tasks.status_id").
+			Where("tasks.sprint_id = ?", sprint.ID).
+			Scan(&current).Error; err != nil {
+			return err
+		}
+
+		// Last burndown point, taken before unfinished tasks leave the sprint
+		if err := recordSprintSnapshots(tx, "sprints.id = ?", sprint.ID); err != nil {
+			return err
+		}
+
+		outcomes := make([]SprintTask, 0, len(current))
+		var unfinished []uint
+		for _, t := range current {
+			done := t.Category == tasks.StatusCategoryDone
+			outcomes = append(outcomes, SprintTask{
+				SprintID:    sprint.ID,
+				TaskID:      t.ID,
+				Completed:   done,
+				CarriedOver: !done,
+			})
+			if !done {
+				unfinished = append(unfinished, t.ID)
+			}
+		}
+
+		if len(outcomes) > 0 {
+			// Existing rows keep their committed flag; tasks added mid-sprint get one
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "sprint_id"}, {Name: "task_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"completed", "carried_over"}),
+			}).Create(&outcomes).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(unfinished) > 0 {
+			if err := tx.Model(&tasks.Task{}).
+				Where("id IN ?", unfinished).
+				Update("sprint_id", nextSprintID).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(sprint).Updates(map[string]interface{}{
+			"state":     SprintStateClosed,
+			"closed_at": closedAt,
+		}).Error
+	})
+}
@@ -0,0 +1,342 @@
+package projects
+
+import (
+	"errors"
+	"fmt"
+	"gotask-backend/models"
+	"gotask-backend/modules/tasks"
+	"time"
+)
+
+type SprintService interface {
+	GetSprints(projectID string, orgID string, userID uint) ([]Sprint, error)
+	GetSprint(id string, orgID string, userID uint) (*Sprint, error)
+	CreateSprint(projectID string, orgID string, userID uint, input SprintInput) (*Sprint, error)
+	UpdateSprint(id string, orgID string, userID uint, input UpdateSprintInput) (*Sprint, error)
+	DeleteSprint(id string, orgID string, userID uint) error
+
+	AddTasks(id string, orgID string, userID uint, taskIDs []uint) error
+	RemoveTask(id string, taskID string, orgID string, userID uint) error
+	StartSprint(id string, orgID string, userID uint) (*Sprint, error)
+	CloseSprint(id string, orgID string, userID uint, nextSprintID *uint) (*SprintReport, error)
+	GetReport(id string, orgID string, userID uint) (*SprintReport, error)
+}
+
+var (
+	ErrSprintNotFound     = errors.New("sprint not found")
+	ErrSprintDates        = fmt.Errorf("end_date must be after start_date and at most %d days later", MaxSprintDays)
+	ErrSprintClosed       = errors.New("sprint is closed")
+	ErrSprintNotPlanned   = errors.New("only a planned sprint can be started")
+	ErrSprintNotActive    = errors.New("only an active sprint can be closed")
+	ErrSprintActive       = errors.New("an active sprint cannot be deleted, close it first")
+	ErrActiveSprintExists = errors.New("the project already has an active sprint")
+	ErrInvalidNextSprint  = errors.New("next sprint must be another open sprint of the same project")
+	ErrInvalidSprintTasks = errors.New("tasks must belong to the sprint's project")
+	ErrTaskNotInSprint    = errors.New("task is not in this sprint")
+)
+
+type sprintService struct {
+	repo           SprintRepository
+	projectService ProjectService
+}
+
+func NewSprintService(repo SprintRepository, projectService ProjectService) SprintService {
+	return &sprintService{repo, projectService}
+}
+
+// Input DTO
+type SprintInput struct {
+	Name      string
+	Goal      string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+type UpdateSprintInput struct {
+	Name      *string
+	Goal      *string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// Helper: loads a sprint and checks the caller's role on its project
+func (s *sprintService) authorize(id string, orgID string, userID uint, minRole string) (*Sprint, *Project, error) {
+	sprint, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, nil, ErrSprintNotFound
+	}
+
+	project, _, err := s.projectService.AuthorizeProject(interfaceToString(sprint.ProjectID), orgID, userID, minRole)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil, nil, ErrSprintNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return sprint, project, nil
+}
+
+func (s *sprintService) GetSprints(projectID string, orgID string, userID uint) ([]Sprint, error) {
+	project, _, err := s.projectService.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByProject(project.ID)
+}
+
+func (s *sprintService) GetSprint(id string, orgID string, userID uint) (*Sprint, error) {
+	sprint, _, err := s.authorize(id, orgID, userID, models.ProjectRoleViewer)
+	return sprint, err
+}
+
+// Helper: a sprint ends after it starts and lasts at most MaxSprintDays
+func checkSprintDates(start time.Time, end time.Time) error {
+	if !end.After(start) || end.After(start.AddDate(0, 0, MaxSprintDays)) {
+		return ErrSprintDates
+	}
+	return nil
+}
+
+func (s *sprintService) CreateSprint(projectID string, orgID string, userID uint, input SprintInput) (*Sprint, error) {
+	project, _, err := s.projectService.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSprintDates(input.StartDate, input.EndDate); err != nil {
+		return nil, err
+	}
+
+	sprint := Sprint{
+		ProjectID: project.ID,
+		Name:      input.Name,
+		Goal:      input.Goal,
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+		State:     SprintStatePlanned,
+	}
+
+	if err := s.repo.Create(&sprint); err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (s *sprintService) UpdateSprint(id string, orgID string, userID uint, input UpdateSprintInput) (*Sprint, error) {
+	sprint, _, err := s.authorize(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.State == SprintStateClosed {
+		return nil, ErrSprintClosed
+	}
+
+	updates := make(map[string]interface{})
+	if input.Name != nil {
+		if *input.Name == "" {
+			return nil, errors.New("sprint name cannot be empty")
+		}
+		updates["name"] = *input.Name
+	}
+	if input.Goal != nil {
+		updates["goal"] = *input.Goal
+	}
+
+	start, end := sprint.StartDate, sprint.EndDate
+	if input.StartDate != nil {
+		start = *input.StartDate
+		updates["start_date"] = start
+	}
+	if input.EndDate != nil {
+		end = *input.EndDate
+		updates["end_date"] = end
+	}
+	if err := checkSprintDates(start, end); err != nil {
+		return nil, err
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.Update(sprint, updates); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindByID(id)
+}
+
+func (s *sprintService) DeleteSprint(id string, orgID string, userID uint) error {
+	sprint, _, err := s.authorize(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return err
+	}
+	if sprint.State == SprintStateActive {
+		return ErrSprintActive
+	}
+	return s.repo.Delete(sprint)
+}
+
+// AddTasks is sprint planning: pulls tasks of the project (from the backlog or another sprint) into this one
+func (s *sprintService) AddTasks(id string, orgID string, userID uint, taskIDs []uint) error {
+	sprint, _, err := s.authorize(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return err
+	}
+	if sprint.State == SprintStateClosed {
+		return ErrSprintClosed
+	}
+
+	count, err := s.repo.CountTasksInProject(sprint.ProjectID, taskIDs)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(uniqueIDs(taskIDs))) {
+		return ErrInvalidSprintTasks
+	}
+
+	return s.repo.SetTasksSprint(sprint.ProjectID, taskIDs, &sprint.ID)
+}
+
+func (s *sprintService) RemoveTask(id string, taskID string, orgID string, userID uint) error {
+	sprint, _, err := s.authorize(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return err
+	}
+	if sprint.State == SprintStateClosed {
+		return ErrSprintClosed
+	}
+
+	removed, err := s.repo.RemoveTask(sprint.ID, taskID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrTaskNotInSprint
+	}
+	return nil
+}
+
+func (s *sprintService) StartSprint(id string, orgID string, userID uint) (*Sprint, error) {
+	sprint, _, err := s.authorize(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.State != SprintStatePlanned {
+		return nil, ErrSprintNotPlanned
+	}
+
+	active, err := s.repo.HasActiveSprint(sprint.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return nil, ErrActiveSprintExists
+	}
+
+	if err := s.repo.Start(sprint, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(id)
+}
+
+// CloseSprint moves unfinished tasks to nextSprintID, or to the backlog when it is nil
+func (s *sprintService) CloseSprint(id string, orgID string, userID uint, nextSprintID *uint) (*SprintReport, error) {
+	sprint, project, err := s.authorize(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.State != SprintStateActive {
+		return nil, ErrSprintNotActive
+	}
+
+	if nextSprintID != nil {
+		next, err := s.repo.FindByID(interfaceToString(*nextSprintID))
+		if err != nil || next.ID == sprint.ID || next.ProjectID != sprint.ProjectID || next.State == SprintStateClosed {
+			return nil, ErrInvalidNextSprint
+		}
+	}
+
+	if err := s.repo.Close(sprint, time.Now(), nextSprintID); err != nil {
+		return nil, err
+	}
+
+	closed, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildReport(closed, project)
+}
+
+func (s *sprintService) GetReport(id string, orgID string, userID uint) (*SprintReport, error) {
+	sprint, project, err := s.authorize(id, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildReport(sprint, project)
+}
+
+// Helper: a closed sprint is reported from its snapshot, an open one from the live task list
+func (s *sprintService) buildReport(sprint *Sprint, project *Project) (*SprintReport, error) {
+	rows, err := s.repo.FindTaskRows(sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	report := SprintReport{Sprint: *sprint, Tasks: make([]SprintTaskRow, 0, len(rows))}
+	for _, row := range rows {
+		inSprint := row.SprintID != nil && *row.SprintID == sprint.ID
+
+		switch sprint.State {
+		case SprintStateClosed:
+			row.Added = !row.Committed
+			row.Removed = row.Committed && !row.Completed && !row.CarriedOver
+		case SprintStateActive:
+			row.Added = !row.Committed && inSprint
+			row.Removed = row.Committed && !inSprint
+			row.Completed = inSprint && row.Category == tasks.StatusCategoryDone
+		default:
+			// Not started yet: everything planned so far is what would be committed
+			row.Committed = inSprint
+			row.Completed = inSprint && row.Category == tasks.StatusCategoryDone
+		}
+
+		if project.Key != "" && row.Number > 0 {
+			row.Key = fmt.Sprintf("%s-%d", project.Key, row.Number)
+		}
+
+		if row.Committed {
+			report.Committed++
+			if row.Completed {
+				report.CommittedCompleted++
+			}
+		}
+		if row.Added {
+			report.Added++
+		}
+		if row.Removed {
+			report.Removed++
+		}
+		if row.Completed {
+			report.Completed++
+		}
+		if row.CarriedOver {
+			report.CarriedOver++
+		}
+		report.Tasks = append(report.Tasks, row)
+	}
+
+	if report.Committed > 0 {
+		report.CompletionRate = float64(report.CommittedCompleted) * 100 / float64(report.Committed)
+	}
+	return &report, nil
+}
+
+// Helper: drops duplicate IDs so counts can be compared
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package search
+
+import (
+	"errors"
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service SearchService
+}
+
+func NewSearchHandler(service SearchService) *Handler {
+	return &Handler{service: service}
+}
+
+// GET /search?q=login bug&type=task,project,comment&limit=20
+func (h *Handler) Search(c *gin.Context) {
+	orgID, exists := c.Get("org_id")
+	if !exists {
+		utils.SendError(c, http.StatusBadRequest, "X-Organization-ID header is required")
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 50 {
+		utils.SendError(c, http.StatusBadRequest, "limit must be a number between 1 and 50")
+		return
+	}
+	var types []string
+	if raw := c.Query("type"); raw != "" {
+		types = strings.Split(raw, ",")
+	}
+
+	results, err := h.service.Search(orgID.(string), user.ID, c.Query("q"), types, limit)
+	if err != nil {
+		if errors.Is(err, ErrEmptyQuery) || errors.Is(err, ErrLongQuery) || errors.Is(err, ErrInvalidType) {
+			utils.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to search")
+		return
+	}
+
+	utils.SendSuccess(c, "success", results)
+}
@@ -0,0 +1,28 @@
+package search
+
+// Kinds of search results
+const (
+	ResultTask    = "task"
+	ResultProject = "project"
+	ResultComment = "comment"
+)
+
+// Result is one hit of GET /search. Snippet is HTML: the matched words are wrapped
+// in <mark> and everything else is escaped.
+type Result struct {
+	Type        string  `json:"type"`
+	ID          uint    `json:"id"`
+	Key         string  `json:"key"`   // "WEB-42" for tasks and comments, the project key for projects
+	Title       string  `json:"title"` // the task title for comments
+	ProjectID   uint    `json:"project_id"`
+	ProjectName string  `json:"project_name"`
+	Snippet     string  `json:"snippet"`
+	Rank        float64 `json:"rank"`
+}
+
+// Query holds the checked parameters of a search
+type Query struct {
+	Text  string
+	Types []string // result kinds to include, empty means all
+	Limit int
+}
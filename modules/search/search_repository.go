@@ -0,0 +1,136 @@
+package search
+
+import (
+	"gotask-backend/models"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Text search configuration of the generated search_vector columns (see config/db.go).
+// "simple" does no stemming, so content in any language matches word for word.
+const textSearchConfig = "simple"
+
+// Options of ts_headline: short snippets with up to two fragments
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2"
+
+type SearchRepository interface {
+	Search(orgID string, userID uint, query Query) ([]Result, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &repository{db}
+}
+
+// A query like "WEB-42" (any case) addresses a task by its key
+var taskKeyQuery = regexp.MustCompile(`^([A-Z][A-Z0-9]*)-([0-9]+)$`)
+
+// Rank of exact key matches, above any ts_rank so they come first
+const keyMatchRank = 1000
+
+// Helper: splits a task key query into project key and number; ok is false for other text
+func parseTaskKey(text string) (projectKey string, number uint64, ok bool) {
+	m := taskKeyQuery.FindStringSubmatch(strings.ToUpper(text))
+	if m == nil {
+		return "", 0, false
+	}
+	number, err := strconv.ParseUint(m[2], 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	return m[1], number, true
+}
+
+// Helper: escapes HTML before ts_headline adds its <mark> tags
+func escapeHTML(column string) string {
+	return "replace(replace(replace(" + column + ", '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+}
+
+// Search ranks tasks, projects and comments of the org that the user can see. Trashed
+// rows, and tasks and comments below them, are left out.
+func (r *repository) Search(orgID string, userID uint, query Query) ([]Result, error) {
+	var parts []interface{}
+	sql := ""
+
+	if wants(query.Types, ResultTask) {
+		// A key query also finds the task by its key; that hit ranks first. Without one
+		// the condition matches nothing (numbers start at 1).
+		projectKey, number, _ := parseTaskKey(query.Text)
+		tasks := r.db.Table("tasks").
+			Select(`'task' AS type, tasks.id, projects.key || '-' || tasks.number AS key, tasks.title,
+				projects.id AS project_id, projects.name AS project_name,
+				ts_headline(CAST(? AS regconfig), `+escapeHTML("tasks.title || ' ' || COALESCE(tasks.description, '')")+`, websearch_to_tsquery(CAST(? AS regconfig), ?), ?) AS snippet,
+				CASE WHEN projects.key = ? AND tasks.number = ? THEN ?
+				ELSE CAST(ts_rank(tasks.search_vector, websearch_to_tsquery(CAST(? AS regconfig), ?)) AS double precision) END AS rank`,
+				textSearchConfig, textSearchConfig, query.Text, headlineOptions, projectKey, number, keyMatchRank, textSearchConfig, query.Text).
+			Joins("JOIN projects ON projects.id = tasks.project_id").
+			Where("projects.organization_id = ? AND projects.deleted_at IS NULL AND tasks.deleted_at IS NULL", orgID).
+			Where("tasks.search_vector @@ websearch_to_tsquery(CAST(? AS regconfig), ?) OR (projects.key = ? AND tasks.number = ?)",
+				textSearchConfig, query.Text, projectKey, number).
+			Scopes(models.VisibleToUser(userID))
+		parts = append(parts, tasks)
+		sql = "(?)"
+	}
+
+	if wants(query.Types, ResultProject) {
+		// The project key itself matches too, ranked first
+		projects := r.db.Table("projects").
+			Select(`'project' AS type, projects.id, projects.key, projects.name AS title,
+				projects.id AS project_id, projects.name AS project_name,
+				ts_headline(CAST(? AS regconfig), `+escapeHTML("projects.name || ' ' || COALESCE(projects.description, '')")+`, websearch_to_tsquery(CAST(? AS regconfig), ?), ?) AS snippet,
+				CASE WHEN projects.key = upper(?) THEN ?
+				ELSE CAST(ts_rank(projects.search_vector, websearch_to_tsquery(CAST(? AS regconfig), ?)) AS double precision) END AS rank`,
+				textSearchConfig, textSearchConfig, query.Text, headlineOptions, query.Text, keyMatchRank, textSearchConfig, query.Text).
+			Where("projects.organization_id = ? AND projects.deleted_at IS NULL", orgID).
+			Where("projects.search_vector @@ websearch_to_tsquery(CAST(? AS regconfig), ?) OR projects.key = upper(?)",
+				textSearchConfig, query.Text, query.Text).
+			Scopes(models.VisibleToUser(userID))
+		if sql != "" {
+			sql += " UNION ALL "
+		}
+		parts = append(parts, projects)
+		sql += "(?)"
+	}
+
+	if wants(query.Types, ResultComment) {
+		comments := r.db.Table("comments").
+			Select(`'comment' AS type, comments.id, projects.key || '-' || tasks.number AS key, tasks.title,
+				projects.id AS project_id, projects.name AS project_name,
+				ts_headline(CAST(? AS regconfig), `+escapeHTML("comments.body")+`, websearch_to_tsquery(CAST(? AS regconfig), ?), ?) AS snippet,
+				CAST(ts_rank(comments.search_vector, websearch_to_tsquery(CAST(? AS regconfig), ?)) AS double precision) AS rank`,
+				textSearchConfig, textSearchConfig, query.Text, headlineOptions, textSearchConfig, query.Text).
+			Joins("JOIN tasks ON tasks.id = comments.task_id").
+			Joins("JOIN projects ON projects.id = tasks.project_id").
+			Where("projects.organization_id = ? AND projects.deleted_at IS NULL AND tasks.deleted_at IS NULL", orgID).
+			Where("comments.search_vector @@ websearch_to_tsquery(CAST(? AS regconfig), ?)", textSearchConfig, query.Text).
+			Scopes(models.VisibleToUser(userID))
+		if sql != "" {
+			sql += " UNION ALL "
+		}
+		parts = append(parts, comments)
+		sql += "(?)"
+	}
+
+	var results []Result
+	parts = append(parts, query.Limit)
+	err := r.db.Raw(sql+" ORDER BY rank DESC, type, id DESC LIMIT ?", parts...).Scan(&results).Error
+	return results, err
+}
+
+func wants(types []string, kind string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+type SearchService interface {
+	Search(orgID string, userID uint, text string, types []string, limit int) ([]Result, error)
+}
+
+var (
+	ErrEmptyQuery  = errors.New("search query must not be empty")
+	ErrLongQuery   = errors.New("search query must be at most 200 characters")
+	ErrInvalidType = errors.New("type must be 'task', 'project' or 'comment'")
+)
+
+type searchService struct {
+	repo SearchRepository
+}
+
+func NewSearchService(repo SearchRepository) SearchService {
+	return &searchService{repo}
+}
+
+func (s *searchService) Search(orgID string, userID uint, text string, types []string, limit int) ([]Result, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrEmptyQuery
+	}
+	if utf8.RuneCountInString(text) > 200 {
+		return nil, ErrLongQuery
+	}
+	for _, t := range types {
+		if t != ResultTask && t != ResultProject && t != ResultComment {
+			return nil, ErrInvalidType
+		}
+	}
+
+	results, err := s.repo.Search(orgID, userID, Query{Text: text, Types: types, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []Result{}
+	}
+	return results, nil
+}
@@ -0,0 +1,151 @@
+package tasks
+
+import (
+	"errors"
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	service AttachmentService
+}
+
+func NewAttachmentHandler(service AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{service: service}
+}
+
+// Helper: attachment errors first, everything else is a task error
+func sendAttachmentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrAttachmentNotFound):
+		utils.SendError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInvalidDownloadLink):
+		utils.SendError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, ErrAttachmentTooLarge):
+		utils.SendError(c, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, ErrAttachmentType):
+		utils.SendError(c, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, ErrInvalidAttachment):
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+	default:
+		sendTaskError(c, err, fallback)
+	}
+}
+
+// POST /tasks/:id/attachments (multipart/form-data with a "file" field)
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	// Room for the multipart framing around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxAttachmentSize+1<<20)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			sendAttachmentError(c, ErrAttachmentTooLarge, "")
+			return
+		}
+		utils.SendError(c, http.StatusBadRequest, "a multipart 'file' field is required")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "could not read the uploaded file")
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Upload(ref, orgID, user.ID, AttachmentUpload{
+		FileName: header.Filename,
+		Size:     header.Size,
+		Content:  file,
+	})
+	if err != nil {
+		sendAttachmentError(c, err, "Failed to upload attachment")
+		return
+	}
+
+	utils.SendSuccess(c, "Attachment uploaded", attachment)
+}
+
+// GET /tasks/:id/attachments
+func (h *AttachmentHandler) FindAttachments(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	attachments, err := h.service.GetAttachments(ref, orgID, user.ID)
+	if err != nil {
+		sendAttachmentError(c, err, "Failed to fetch attachments")
+		return
+	}
+
+	utils.SendSuccess(c, "success", attachments)
+}
+
+// GET /attachments/:id
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	attachment, err := h.service.GetAttachment(c.Param("id"), orgID, user.ID)
+	if err != nil {
+		sendAttachmentError(c, err, "Failed to fetch attachment")
+		return
+	}
+
+	utils.SendSuccess(c, "success", attachment)
+}
+
+// DELETE /attachments/:id
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteAttachment(c.Param("id"), orgID, user.ID); err != nil {
+		sendAttachmentError(c, err, "Failed to delete attachment")
+		return
+	}
+
+	utils.SendSuccess(c, "Attachment deleted")
+}
+
+// GET /attachments/:id/download?expires=&signature= (public, the signature is the authorization)
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	attachment, file, err := h.service.OpenDownload(c.Param("id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		sendAttachmentError(c, err, "Failed to download attachment")
+		return
+	}
+	defer file.Close()
+
+	// Images may show in the page, anything else is always saved
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, file, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=900",
+	})
+}
@@ -0,0 +1,44 @@
+package tasks
+
+import (
+	"io"
+	"time"
+)
+
+// MaxAttachmentSize caps uploads, in bytes
+const MaxAttachmentSize = 25 << 20
+
+// Content types accepted for attachments, as sniffed from the file itself.
+// Office documents are zip archives; SVG is left out because it can carry scripts.
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"application/zip": true,
+	"text/plain":      true,
+}
+
+// Attachment is a file uploaded to a task. The bytes live in the configured
+// storage under StorageKey; rows go away when their task is purged from the trash.
+type Attachment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TaskID      uint      `gorm:"index" json:"task_id"`
+	UploadedBy  uint      `json:"uploaded_by"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`     // bytes
+	Checksum    string    `json:"checksum"` // hex SHA-256 of the content
+	StorageKey  string    `gorm:"uniqueIndex" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	DownloadURL string `gorm:"-" json:"download_url"` // signed, see AttachmentLinkTTL
+}
+
+// AttachmentUpload is a file on its way in
+type AttachmentUpload struct {
+	FileName string
+	Size     int64
+	Content  io.Reader
+}
@@ -0,0 +1,56 @@
+package tasks
+
+import "gorm.io/gorm"
+
+type AttachmentRepository interface {
+	Create(attachment *Attachment) error
+	FindByID(id string) (*Attachment, error)
+	FindByTaskID(taskID uint) ([]Attachment, error)
+	Delete(attachment *Attachment) error
+	FindOrphans(afterID uint, limit int) ([]Attachment, error)
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db}
+}
+
+func (r *attachmentRepository) Create(attachment *Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+// FindByID only finds attachments of tasks that are not in the trash
+func (r *attachmentRepository) FindByID(id string) (*Attachment, error) {
+	var attachment Attachment
+	err := r.db.
+		Joins("JOIN tasks ON tasks.id = attachments.task_id AND tasks.deleted_at IS NULL").
+		Where("attachments.id = ?", id).
+		First(&attachment).Error
+	return &attachment, err
+}
+
+func (r *attachmentRepository) FindByTaskID(taskID uint) ([]Attachment, error) {
+	var attachments []Attachment
+	err := r.db.Where("task_id = ?", taskID).Order("created_at asc, id asc").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) Delete(attachment *Attachment) error {
+	return r.db.Delete(attachment).Error
+}
+
+// FindOrphans returns attachments whose task has been purged for good, in id order
+// starting after afterID
+func (r *attachmentRepository) FindOrphans(afterID uint, limit int) ([]Attachment, error) {
+	var orphans []Attachment
+	err := r.db.
+		Where("NOT EXISTS (SELECT 1 FROM tasks WHERE tasks.id = attachments.task_id)").
+		Where("id > ?", afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&orphans).Error
+	return orphans, err
+}
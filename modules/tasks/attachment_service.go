@@ -0,0 +1,278 @@
+package tasks
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gotask-backend/models"
+	"gotask-backend/storage"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type AttachmentService interface {
+	Upload(ref string, orgID string, userID uint, upload AttachmentUpload) (*Attachment, error)
+	GetAttachments(ref string, orgID string, userID uint) ([]Attachment, error)
+	GetAttachment(id string, orgID string, userID uint) (*Attachment, error)
+	DeleteAttachment(id string, orgID string, userID uint) error
+	OpenDownload(id string, expires string, signature string) (*Attachment, io.ReadCloser, error)
+	PurgeOrphans() error
+}
+
+var (
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+	ErrInvalidAttachment   = errors.New("file must not be empty")
+	ErrAttachmentTooLarge  = fmt.Errorf("attachments must be at most %d MB", MaxAttachmentSize>>20)
+	ErrAttachmentType      = errors.New("file type not allowed, use PNG, JPEG, GIF, WebP, PDF, plain text or zip files")
+	ErrInvalidDownloadLink = errors.New("download link is invalid or has expired")
+)
+
+// AttachmentLinkTTL is how long a download URL stays valid after it was handed out
+const AttachmentLinkTTL = 15 * time.Minute
+
+type attachmentService struct {
+	repo        AttachmentRepository
+	taskService TaskService
+	store       storage.Storage
+	secret      []byte // signs download URLs
+}
+
+func NewAttachmentService(repo AttachmentRepository, taskService TaskService, store storage.Storage, secret []byte) AttachmentService {
+	return &attachmentService{repo, taskService, store, secret}
+}
+
+// Helper: loads an attachment and checks that the caller can see its task
+func (s *attachmentService) authorizeAttachment(id string, orgID string, userID uint) (*Attachment, *Task, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	attachment, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	task, err := s.taskService.GetTask(interfaceToString(attachment.TaskID), orgID, userID)
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, task, nil
+}
+
+// Upload stores a file for a task. The content type is sniffed from the bytes, never
+// taken from the client, and the checksum is computed while the file streams through.
+func (s *attachmentService) Upload(ref string, orgID string, userID uint, upload AttachmentUpload) (*Attachment, error) {
+	task, err := s.taskService.GetTask(ref, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.taskService.AuthorizeProject(interfaceToString(task.ProjectID), orgID, userID, models.ProjectRoleContributor); err != nil {
+		return nil, err
+	}
+	if upload.Size <= 0 {
+		return nil, ErrInvalidAttachment
+	}
+	if upload.Size > MaxAttachmentSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	content := bufio.NewReaderSize(io.LimitReader(upload.Content, upload.Size), 512)
+	head, err := content.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !allowedAttachmentTypes[mediaType] {
+		return nil, ErrAttachmentType
+	}
+
+	key, err := attachmentKey(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	if err := s.store.Put(key, io.TeeReader(content, hash), upload.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := Attachment{
+		TaskID:      task.ID,
+		UploadedBy:  userID,
+		FileName:    cleanFileName(upload.FileName),
+		ContentType: contentType,
+		Size:        upload.Size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  key,
+	}
+	if err := s.repo.Create(&attachment); err != nil {
+		_ = s.store.Delete(key)
+		return nil, err
+	}
+
+	s.sign(&attachment)
+	return &attachment, nil
+}
+
+func (s *attachmentService) GetAttachments(ref string, orgID string, userID uint) ([]Attachment, error) {
+	task, err := s.taskService.GetTask(ref, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.repo.FindByTaskID(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if attachments == nil {
+		attachments = []Attachment{}
+	}
+	for i := range attachments {
+		s.sign(&attachments[i])
+	}
+	return attachments, nil
+}
+
+// GetAttachment returns the attachment with a fresh download URL
+func (s *attachmentService) GetAttachment(id string, orgID string, userID uint) (*Attachment, error) {
+	attachment, _, err := s.authorizeAttachment(id, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(attachment)
+	return attachment, nil
+}
+
+// DeleteAttachment is open to the uploader and to project leads
+func (s *attachmentService) DeleteAttachment(id string, orgID string, userID uint) error {
+	attachment, task, err := s.authorizeAttachment(id, orgID, userID)
+	if err != nil {
+		return err
+	}
+	minRole := models.ProjectRoleContributor
+	if attachment.UploadedBy != userID {
+		minRole = models.ProjectRoleLead
+	}
+	if err := s.taskService.AuthorizeProject(interfaceToString(task.ProjectID), orgID, userID, minRole); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(attachment); err != nil {
+		return err
+	}
+	// The row is gone, a leftover file is only wasted space
+	if err := s.store.Delete(attachment.StorageKey); err != nil {
+		log.Printf("delete attachment file %s failed: %v", attachment.StorageKey, err)
+	}
+	return nil
+}
+
+// OpenDownload checks a signed download URL and opens the file; the caller closes it
+func (s *attachmentService) OpenDownload(id string, expires string, signature string) (*Attachment, io.ReadCloser, error) {
+	attachmentID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, nil, ErrInvalidDownloadLink
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, nil, ErrInvalidDownloadLink
+	}
+	expected := s.signature(uint(attachmentID), expiresAt)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, nil, ErrInvalidDownloadLink
+	}
+
+	attachment, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	file, err := s.store.Open(attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, file, nil
+}
+
+// PurgeOrphans deletes the files and rows of attachments whose task was purged from the
+// trash. A file that cannot be deleted is logged and its row kept for the next run; the
+// remaining orphans are still purged and the failures are returned together.
+func (s *attachmentService) PurgeOrphans() error {
+	var failures []error
+	var afterID uint
+	for {
+		orphans, err := s.repo.FindOrphans(afterID, 100)
+		if err != nil {
+			return errors.Join(append(failures, err)...)
+		}
+		if len(orphans) == 0 {
+			return errors.Join(failures...)
+		}
+		for i := range orphans {
+			afterID = orphans[i].ID
+			// A file that is already gone only leaves the row to delete
+			if err := s.store.Delete(orphans[i].StorageKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				log.Printf("delete attachment file %s failed: %v", orphans[i].StorageKey, err)
+				failures = append(failures, err)
+				continue
+			}
+			if err := s.repo.Delete(&orphans[i]); err != nil {
+				return errors.Join(append(failures, err)...)
+			}
+		}
+	}
+}
+
+// Helper: fills the download URL, valid for AttachmentLinkTTL
+func (s *attachmentService) sign(attachment *Attachment) {
+	expires := time.Now().Add(AttachmentLinkTTL).Unix()
+	attachment.DownloadURL = fmt.Sprintf("/attachments/%d/download?expires=%d&signature=%s",
+		attachment.ID, expires, s.signature(attachment.ID, expires))
+}
+
+func (s *attachmentService) signature(id uint, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "attachment:%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Helper: a random, unguessable storage key grouped by task
+func attachmentKey(taskID uint) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(random)), nil
+}
+
+// Helper: keeps the base name of the uploaded file without control characters
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
@@ -0,0 +1,242 @@
+package tasks
+
+import (
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Body of one new checklist item
+type checklistItemRequest struct {
+	Text       string     `json:"text" binding:"required"`
+	AssigneeID *uint      `json:"assignee_id"`
+	DueDate    *time.Time `json:"due_date"`
+}
+
+func (r checklistItemRequest) input() ChecklistItemInput {
+	return ChecklistItemInput{Text: r.Text, AssigneeID: r.AssigneeID, DueDate: r.DueDate}
+}
+
+// GET /tasks/:id/checklist
+func (h *Handler) GetChecklist(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	items, err := h.service.GetChecklist(ref, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch checklist")
+		return
+	}
+
+	utils.SendSuccess(c, "success", items)
+}
+
+// POST /tasks/:id/checklist {"text": "Update changelog", "assignee_id": 4, "due_date": "2024-06-01T00:00:00Z"}
+func (h *Handler) AddChecklistItem(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req checklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := h.service.AddChecklistItems(ref, orgID, user.ID, []ChecklistItemInput{req.input()})
+	if err != nil {
+		sendTaskError(c, err, "Failed to add checklist item")
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist item added", items)
+}
+
+// POST /tasks/:id/checklist/bulk {"items": [{"text": "..."}, ...]}
+func (h *Handler) AddChecklistItems(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Items []checklistItemRequest `json:"items" binding:"required,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	inputs := make([]ChecklistItemInput, 0, len(req.Items))
+	for _, item := range req.Items {
+		inputs = append(inputs, item.input())
+	}
+	items, err := h.service.AddChecklistItems(ref, orgID, user.ID, inputs)
+	if err != nil {
+		sendTaskError(c, err, "Failed to add checklist items")
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist items added", items)
+}
+
+// PUT /tasks/:id/checklist/order {"item_ids": [3, 1, 2]}
+func (h *Handler) ReorderChecklist(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		ItemIDs []uint `json:"item_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := h.service.ReorderChecklist(ref, orgID, user.ID, req.ItemIDs)
+	if err != nil {
+		sendTaskError(c, err, "Failed to reorder checklist")
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist reordered", items)
+}
+
+// PATCH /tasks/:id/checklist {"done": true, "item_ids": [1, 2]} (no item_ids means every item)
+func (h *Handler) SetChecklistDone(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Done    *bool  `json:"done" binding:"required"`
+		ItemIDs []uint `json:"item_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := h.service.SetChecklistDone(ref, orgID, user.ID, req.ItemIDs, *req.Done)
+	if err != nil {
+		sendTaskError(c, err, "Failed to update checklist")
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist updated", items)
+}
+
+// DELETE /tasks/:id/checklist?done=true (only the checked items when done=true)
+func (h *Handler) ClearChecklist(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	doneOnly, err := strconv.ParseBool(c.DefaultQuery("done", "false"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "done must be 'true' or 'false'")
+		return
+	}
+
+	deleted, err := h.service.ClearChecklist(ref, orgID, user.ID, doneOnly)
+	if err != nil {
+		sendTaskError(c, err, "Failed to clear checklist")
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist cleared", gin.H{"deleted": deleted})
+}
+
+// PATCH /checklist-items/:id {"text", "done", "assignee_id" (0 removes), "due_date", "clear_due_date"}
+func (h *Handler) UpdateChecklistItem(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Text         *string    `json:"text"`
+		Done         *bool      `json:"done"`
+		AssigneeID   *uint      `json:"assignee_id"`
+		DueDate      *time.Time `json:"due_date"`
+		ClearDueDate bool       `json:"clear_due_date"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item, err := h.service.UpdateChecklistItem(id, orgID, user.ID, UpdateChecklistItemInput{
+		Text:         req.Text,
+		Done:         req.Done,
+		AssigneeID:   req.AssigneeID,
+		DueDate:      req.DueDate,
+		ClearDueDate: req.ClearDueDate,
+	})
+	if err != nil {
+		sendTaskError(c, err, "Failed to update checklist item")
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist item updated", item)
+}
+
+// POST /checklist-items/:id/toggle
+func (h *Handler) ToggleChecklistItem(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	item, err := h.service.ToggleChecklistItem(id, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to update checklist item")
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist item updated", item)
+}
+
+// DELETE /checklist-items/:id
+func (h *Handler) DeleteChecklistItem(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteChecklistItem(id, orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to delete checklist item")
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist item deleted")
+}
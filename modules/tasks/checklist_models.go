@@ -0,0 +1,27 @@
+package tasks
+
+import "time"
+
+// Limits of a task's checklist
+const (
+	MaxChecklistItems      = 100
+	MaxChecklistTextLength = 500 // characters
+)
+
+// ChecklistItem is a small to-do inside a task, too light to be a subtask
+type ChecklistItem struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	TaskID     uint       `gorm:"index" json:"task_id"`
+	Position   int        `json:"position"` // order within the task, lowest first
+	Text       string     `json:"text"`
+	Done       bool       `json:"done"`
+	AssigneeID *uint      `json:"assignee_id"` // a member of the task's project
+	DueDate    *time.Time `json:"due_date"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ChecklistProgress counts the checklist items of a task
+type ChecklistProgress struct {
+	Total int64 `json:"total"`
+	Done  int64 `json:"done"`
+}
@@ -0,0 +1,134 @@
+package tasks
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) FindChecklist(taskID uint) ([]ChecklistItem, error) {
+	var items []ChecklistItem
+	err := r.db.Where("task_id = ?", taskID).Order("position asc, id asc").Find(&items).Error
+	return items, err
+}
+
+func (r *repository) FindChecklistItemByID(id string) (*ChecklistItem, error) {
+	var item ChecklistItem
+	err := r.db.Where("id = ?", id).First(&item).Error
+	return &item, err
+}
+
+// CreateChecklistItems appends items to the end of a task's checklist. The task row
+// is locked so concurrent additions do not get the same positions or go past
+// MaxChecklistItems together.
+func (r *repository) CreateChecklistItems(taskID uint, items []ChecklistItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", taskID).First(&Task{}).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&ChecklistItem{}).Where("task_id = ?", taskID).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count)+len(items) > MaxChecklistItems {
+			return invalidChecklist("a task can have at most %d checklist items", MaxChecklistItems)
+		}
+		var next int
+		if err := tx.Model(&ChecklistItem{}).Where("task_id = ?", taskID).
+			Select("COALESCE(MAX(position), -1) + 1").Scan(&next).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].TaskID = taskID
+			items[i].Position = next + i
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+func (r *repository) UpdateChecklistItem(item *ChecklistItem, updates map[string]interface{}) error {
+	return r.db.Model(item).Updates(updates).Error
+}
+
+// ToggleChecklistItem flips the done flag in the database, so concurrent toggles do not
+// both write the same value, and stores the new flag in item
+func (r *repository) ToggleChecklistItem(item *ChecklistItem) error {
+	result := r.db.Raw("UPDATE checklist_items SET done = NOT done WHERE id = ? RETURNING done", item.ID).Scan(&item.Done)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrChecklistItemNotFound
+	}
+	return nil
+}
+
+func (r *repository) DeleteChecklistItem(item *ChecklistItem) error {
+	return r.db.Delete(item).Error
+}
+
+// ReorderChecklist gives the items the positions of their place in itemIDs
+func (r *repository) ReorderChecklist(taskID uint, itemIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, id := range itemIDs {
+			if err := tx.Model(&ChecklistItem{}).Where("id = ? AND task_id = ?", id, taskID).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetChecklistDone checks or unchecks the given items of a task, or all of them when itemIDs is empty
+func (r *repository) SetChecklistDone(taskID uint, itemIDs []uint, done bool) error {
+	query := r.db.Model(&ChecklistItem{}).Where("task_id = ?", taskID)
+	if len(itemIDs) > 0 {
+		query = query.Where("id IN ?", itemIDs)
+	}
+	return query.Update("done", done).Error
+}
+
+// DeleteChecklistItems clears a task's checklist, or only its checked items
+func (r *repository) DeleteChecklistItems(taskID uint, doneOnly bool) (int64, error) {
+	query := r.db.Where("task_id = ?", taskID)
+	if doneOnly {
+		query = query.Where("done = ?", true)
+	}
+	result := query.Delete(&ChecklistItem{})
+	return result.RowsAffected, result.Error
+}
+
+// Helper: counts the checklist items of each task that has a checklist
+func (r *repository) fillChecklistProgress(tasks []Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	var rows []struct {
+		TaskID uint
+		Total  int64
+		Done   int64
+	}
+	err := r.db.Model(&ChecklistItem{}).
+		Select("task_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE done) AS done").
+		Where("task_id IN ?", ids).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	progress := make(map[uint]*ChecklistProgress)
+	for _, row := range rows {
+		progress[row.TaskID] = &ChecklistProgress{Total: row.Total, Done: row.Done}
+	}
+	for i := range tasks {
+		tasks[i].Checklist = progress[tasks[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"gotask-backend/models"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrInvalidChecklist      = errors.New("invalid checklist")
+)
+
+// ChecklistItemInput is a new checklist item
+type ChecklistItemInput struct {
+	Text       string
+	AssigneeID *uint
+	DueDate    *time.Time
+}
+
+type UpdateChecklistItemInput struct {
+	Text         *string
+	Done         *bool
+	AssigneeID   *uint // 0 removes the assignee
+	DueDate      *time.Time
+	ClearDueDate bool
+}
+
+// Helper: wraps ErrInvalidChecklist with the reason
+func invalidChecklist(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidChecklist, fmt.Sprintf(format, args...))
+}
+
+// Helper: trims the text of an item and checks its length
+func checkChecklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", invalidChecklist("text is required")
+	}
+	if utf8.RuneCountInString(text) > MaxChecklistTextLength {
+		return "", invalidChecklist("text must be at most %d characters", MaxChecklistTextLength)
+	}
+	return text, nil
+}
+
+// Helper: checklist items can only be given to members of the task's project
+func (s *taskService) checkChecklistAssignee(assigneeID uint, projectID uint) error {
+	members, err := s.repo.FilterProjectMembers(projectID, []uint{assigneeID})
+	if err != nil {
+		return err
+	}
+	if !containsUint(members, assigneeID) {
+		return ErrInvalidAssignees
+	}
+	return nil
+}
+
+// Helper: loads a checklist item and checks the caller's role on the project of its task
+func (s *taskService) authorizeChecklistItem(id string, orgID string, userID uint, minRole string) (*ChecklistItem, *Task, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, nil, ErrChecklistItemNotFound
+	}
+	item, err := s.repo.FindChecklistItemByID(id)
+	if err != nil {
+		return nil, nil, ErrChecklistItemNotFound
+	}
+	task, err := s.authorizeTask(interfaceToString(item.TaskID), orgID, userID, minRole)
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, nil, ErrChecklistItemNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, task, nil
+}
+
+// Helper: the checklist of a task, never nil
+func (s *taskService) checklist(taskID uint) ([]ChecklistItem, error) {
+	items, err := s.repo.FindChecklist(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []ChecklistItem{}
+	}
+	return items, nil
+}
+
+func (s *taskService) GetChecklist(ref string, orgID string, userID uint) ([]ChecklistItem, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.checklist(task.ID)
+}
+
+// AddChecklistItems appends one or more items to the end of the checklist
+// and returns the whole checklist
+func (s *taskService) AddChecklistItems(ref string, orgID string, userID uint, inputs []ChecklistItemInput) ([]ChecklistItem, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+	if len(inputs) == 0 {
+		return nil, invalidChecklist("at least one item is required")
+	}
+	if len(inputs) > MaxChecklistItems {
+		return nil, invalidChecklist("a task can have at most %d checklist items", MaxChecklistItems)
+	}
+
+	items := make([]ChecklistItem, 0, len(inputs))
+	for _, input := range inputs {
+		text, err := checkChecklistText(input.Text)
+		if err != nil {
+			return nil, err
+		}
+		if input.AssigneeID != nil {
+			if err := s.checkChecklistAssignee(*input.AssigneeID, task.ProjectID); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, ChecklistItem{Text: text, AssigneeID: input.AssigneeID, DueDate: input.DueDate})
+	}
+
+	if err := s.repo.CreateChecklistItems(task.ID, items); err != nil {
+		return nil, err
+	}
+	return s.checklist(task.ID)
+}
+
+func (s *taskService) UpdateChecklistItem(id string, orgID string, userID uint, input UpdateChecklistItemInput) (*ChecklistItem, error) {
+	item, task, err := s.authorizeChecklistItem(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if input.Text != nil {
+		text, err := checkChecklistText(*input.Text)
+		if err != nil {
+			return nil, err
+		}
+		updates["text"] = text
+	}
+	if input.Done != nil {
+		updates["done"] = *input.Done
+	}
+	if input.AssigneeID != nil {
+		if *input.AssigneeID == 0 {
+			updates["assignee_id"] = nil
+		} else {
+			if err := s.checkChecklistAssignee(*input.AssigneeID, task.ProjectID); err != nil {
+				return nil, err
+			}
+			updates["assignee_id"] = *input.AssigneeID
+		}
+	}
+	if input.ClearDueDate {
+		updates["due_date"] = nil
+	} else if input.DueDate != nil {
+		updates["due_date"] = *input.DueDate
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.UpdateChecklistItem(item, updates); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindChecklistItemByID(id)
+}
+
+// ToggleChecklistItem flips the done flag of an item
+func (s *taskService) ToggleChecklistItem(id string, orgID string, userID uint) (*ChecklistItem, error) {
+	item, _, err := s.authorizeChecklistItem(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ToggleChecklistItem(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *taskService) DeleteChecklistItem(id string, orgID string, userID uint) error {
+	item, _, err := s.authorizeChecklistItem(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteChecklistItem(item)
+}
+
+// ReorderChecklist takes every item ID of the checklist once, in the new order
+func (s *taskService) ReorderChecklist(ref string, orgID string, userID uint, itemIDs []uint) ([]ChecklistItem, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.FindChecklist(task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(itemIDs) != len(items) {
+		return nil, invalidChecklist("item_ids must list all %d items of the checklist", len(items))
+	}
+	seen := make(map[uint]bool)
+	for _, id := range itemIDs {
+		if seen[id] || !checklistContains(items, id) {
+			return nil, invalidChecklist("item_ids must list all %d items of the checklist", len(items))
+		}
+		seen[id] = true
+	}
+
+	if err := s.repo.ReorderChecklist(task.ID, itemIDs); err != nil {
+		return nil, err
+	}
+	return s.checklist(task.ID)
+}
+
+// SetChecklistDone checks or unchecks several items at once, all of them when itemIDs is empty
+func (s *taskService) SetChecklistDone(ref string, orgID string, userID uint, itemIDs []uint, done bool) ([]ChecklistItem, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.FindChecklist(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range itemIDs {
+		if !checklistContains(items, id) {
+			return nil, invalidChecklist("item %d is not on this checklist", id)
+		}
+	}
+
+	if err := s.repo.SetChecklistDone(task.ID, itemIDs, done); err != nil {
+		return nil, err
+	}
+	return s.checklist(task.ID)
+}
+
+// ClearChecklist deletes the checklist of a task, or only its checked items
+func (s *taskService) ClearChecklist(ref string, orgID string, userID uint, doneOnly bool) (int64, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.DeleteChecklistItems(task.ID, doneOnly)
+}
+
+func checklistContains(items []ChecklistItem, id uint) bool {
+	for _, item := range items {
+		if item.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package tasks
+
+import (
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /tasks/:id/comments?page=1&limit=20
+func (h *Handler) GetComments(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		utils.SendError(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		return
+	}
+
+	comments, total, err := h.service.GetComments(ref, orgID, user.ID, page, limit)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch comments")
+		return
+	}
+
+	utils.SendSuccess(c, "success", gin.H{
+		"comments": comments,
+		"meta": gin.H{
+			"current_page": page,
+			"limit":        limit,
+			"total_data":   total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// POST /tasks/:id/comments {"body": "Looks good @alice", "parent_id": 3}
+func (h *Handler) CreateComment(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Body     string `json:"body" binding:"required"`
+		ParentID *uint  `json:"parent_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := h.service.CreateComment(ref, orgID, user.ID, CommentInput{
+		Body:     req.Body,
+		ParentID: req.ParentID,
+	})
+	if err != nil {
+		sendTaskError(c, err, "Failed to add comment")
+		return
+	}
+
+	utils.SendSuccess(c, "Comment added", comment)
+}
+
+// PATCH /comments/:id {"body": "..."}
+func (h *Handler) UpdateComment(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Body string `json:"body" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := h.service.UpdateComment(id, orgID, user.ID, req.Body)
+	if err != nil {
+		sendTaskError(c, err, "Failed to update comment")
+		return
+	}
+
+	utils.SendSuccess(c, "Comment updated", comment)
+}
+
+// DELETE /comments/:id
+func (h *Handler) DeleteComment(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteComment(id, orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to delete comment")
+		return
+	}
+
+	utils.SendSuccess(c, "Comment deleted")
+}
+
+// GET /comments/:id/history
+func (h *Handler) GetCommentHistory(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	revisions, err := h.service.GetCommentHistory(id, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch comment history")
+		return
+	}
+
+	utils.SendSuccess(c, "success", revisions)
+}
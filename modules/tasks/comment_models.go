@@ -0,0 +1,31 @@
+package tasks
+
+import "time"
+
+// MaxCommentLength caps comment bodies, in characters
+const MaxCommentLength = 10000
+
+// Comment is a Markdown note on a task. Replies hang under a top-level comment,
+// threads are only one level deep.
+type Comment struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	TaskID     uint       `gorm:"index" json:"task_id"`
+	ParentID   *uint      `gorm:"index" json:"parent_id"` // nil for top-level comments
+	AuthorID   uint       `json:"author_id"`
+	Body       string     `gorm:"type:text" json:"body"`                         // Markdown as written
+	BodyHTML   string     `gorm:"type:text" json:"body_html"`                    // sanitized rendering with mentions and references linked
+	MentionIDs []uint     `gorm:"type:jsonb;serializer:json" json:"mention_ids"` // users @mentioned in the body
+	EditedAt   *time.Time `json:"edited_at"`                                     // nil until the first edit
+	CreatedAt  time.Time  `json:"created_at"`
+
+	Replies []Comment `gorm:"-" json:"replies,omitempty"` // only filled on top-level comments
+}
+
+// CommentRevision keeps the body a comment had before one of its edits
+type CommentRevision struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CommentID uint      `gorm:"index" json:"comment_id"`
+	Body      string    `gorm:"type:text" json:"body"`
+	EditedBy  uint      `json:"edited_by"`
+	EditedAt  time.Time `json:"edited_at"` // when this body was replaced
+}
@@ -0,0 +1,103 @@
+package tasks
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) CreateComment(comment *Comment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *repository) FindCommentByID(id string) (*Comment, error) {
+	var comment Comment
+	err := r.db.Where("id = ?", id).First(&comment).Error
+	return &comment, err
+}
+
+// FindComments pages through the top-level comments of a task, oldest first,
+// each with all of its replies
+func (r *repository) FindComments(taskID uint, page int, limit int) ([]Comment, int64, error) {
+	var comments []Comment
+	var total int64
+
+	if err := r.db.Model(&Comment{}).Where("task_id = ? AND parent_id IS NULL", taskID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := r.db.Where("task_id = ? AND parent_id IS NULL", taskID).
+		Order("created_at asc, id asc").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&comments).Error
+	if err != nil || len(comments) == 0 {
+		return comments, total, err
+	}
+
+	ids := make([]uint, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+	var replies []Comment
+	err = r.db.Where("parent_id IN ?", ids).Order("created_at asc, id asc").Find(&replies).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	index := make(map[uint]int, len(comments))
+	for i, comment := range comments {
+		index[comment.ID] = i
+	}
+	for _, reply := range replies {
+		parent := &comments[index[*reply.ParentID]]
+		parent.Replies = append(parent.Replies, reply)
+	}
+	return comments, total, nil
+}
+
+// UpdateComment saves the previous body as a revision and applies the edit in one go
+func (r *repository) UpdateComment(comment *Comment, revision *CommentRevision, rendered *renderedMarkdown, body string) error {
+	mentionIDs, err := json.Marshal(rendered.MentionIDs)
+	if err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+		return tx.Model(comment).Updates(map[string]interface{}{
+			"body":        body,
+			"body_html":   rendered.HTML,
+			"mention_ids": string(mentionIDs),
+			"edited_at":   revision.EditedAt,
+		}).Error
+	})
+}
+
+// DeleteComment removes a comment for good, together with its replies, the
+// revisions of all of them and the notifications pointing at them
+func (r *repository) DeleteComment(comment *Comment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids := []uint{comment.ID}
+		var replyIDs []uint
+		if err := tx.Model(&Comment{}).Where("parent_id = ?", comment.ID).Pluck("id", &replyIDs).Error; err != nil {
+			return err
+		}
+		ids = append(ids, replyIDs...)
+
+		if err := tx.Exec("DELETE FROM notifications WHERE comment_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id IN ?", ids).Delete(&CommentRevision{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&Comment{}).Error
+	})
+}
+
+// FindCommentRevisions lists the earlier bodies of a comment, newest first
+func (r *repository) FindCommentRevisions(commentID uint) ([]CommentRevision, error) {
+	var revisions []CommentRevision
+	err := r.db.Where("comment_id = ?", commentID).Order("edited_at desc, id desc").Find(&revisions).Error
+	return revisions, err
+}
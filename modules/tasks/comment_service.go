@@ -0,0 +1,253 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"gotask-backend/models"
+	"gotask-backend/modules/notifications"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrInvalidComment   = errors.New("invalid comment")
+	ErrNotCommentAuthor = errors.New("only the author can edit this comment")
+)
+
+// CommentInput is a new comment; ParentID makes it a reply
+type CommentInput struct {
+	Body     string
+	ParentID *uint
+}
+
+// Helper: wraps ErrInvalidComment with the reason
+func invalidComment(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidComment, fmt.Sprintf(format, args...))
+}
+
+// Helper: comments need a body within MaxCommentLength
+func checkCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return invalidComment("body is required")
+	}
+	if utf8.RuneCountInString(body) > MaxCommentLength {
+		return invalidComment("body must be at most %d characters", MaxCommentLength)
+	}
+	return nil
+}
+
+// Helper: loads a comment and checks the caller's role on the project of its task.
+// Comments on tasks the caller cannot see are reported as not found.
+func (s *taskService) authorizeComment(id string, orgID string, userID uint, minRole string) (*Comment, *Task, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, nil, ErrCommentNotFound
+	}
+	comment, err := s.repo.FindCommentByID(id)
+	if err != nil {
+		return nil, nil, ErrCommentNotFound
+	}
+	task, err := s.authorizeTask(interfaceToString(comment.TaskID), orgID, userID, minRole)
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return comment, task, nil
+}
+
+// GetComments pages through the threads of a task
+func (s *taskService) GetComments(ref string, orgID string, userID uint, page int, limit int) ([]Comment, int64, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	comments, total, err := s.repo.FindComments(task.ID, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if comments == nil {
+		comments = []Comment{}
+	}
+	return comments, total, nil
+}
+
+func (s *taskService) CreateComment(ref string, orgID string, userID uint, input CommentInput) (*Comment, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCommentBody(input.Body); err != nil {
+		return nil, err
+	}
+
+	var parent *Comment
+	if input.ParentID != nil {
+		parent, err = s.repo.FindCommentByID(interfaceToString(*input.ParentID))
+		if err != nil || parent.TaskID != task.ID {
+			return nil, invalidComment("parent must be a comment on the same task")
+		}
+		if parent.ParentID != nil {
+			return nil, invalidComment("replies can only be made to top-level comments")
+		}
+	}
+
+	rendered, err := s.renderMarkdown(task.ProjectID, orgID, userID, input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := Comment{
+		TaskID:     task.ID,
+		ParentID:   input.ParentID,
+		AuthorID:   userID,
+		Body:       input.Body,
+		BodyHTML:   rendered.HTML,
+		MentionIDs: rendered.MentionIDs,
+	}
+	if err := s.repo.CreateComment(&comment); err != nil {
+		return nil, err
+	}
+
+	s.subscribe(task.ID, append([]uint{userID}, rendered.MentionIDs...))
+	s.notifyComment(orgID, task, &comment, parent)
+	return &comment, nil
+}
+
+// UpdateComment lets the author rewrite a comment; the old body goes to its history
+// and only people mentioned for the first time are notified
+func (s *taskService) UpdateComment(id string, orgID string, userID uint, body string) (*Comment, error) {
+	comment, task, err := s.authorizeComment(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+	if err := checkCommentBody(body); err != nil {
+		return nil, err
+	}
+	if body == comment.Body {
+		return comment, nil
+	}
+
+	rendered, err := s.renderMarkdown(task.ProjectID, orgID, userID, body)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := CommentRevision{
+		CommentID: comment.ID,
+		Body:      comment.Body,
+		EditedBy:  userID,
+		EditedAt:  time.Now(),
+	}
+	if err := s.repo.UpdateComment(comment, &revision, rendered, body); err != nil {
+		return nil, err
+	}
+
+	var added []uint
+	for _, mentionID := range rendered.MentionIDs {
+		if !containsUint(comment.MentionIDs, mentionID) {
+			added = append(added, mentionID)
+		}
+	}
+	comment.Body = body
+	comment.BodyHTML = rendered.HTML
+	comment.MentionIDs = rendered.MentionIDs
+	comment.EditedAt = &revision.EditedAt
+
+	s.subscribe(task.ID, added)
+	s.notifyMentions(orgID, task, comment, onlyRecipients(added, s.recipients(task)))
+	return comment, nil
+}
+
+// DeleteComment is open to the author and to project leads; replies go with the comment
+func (s *taskService) DeleteComment(id string, orgID string, userID uint) error {
+	comment, task, err := s.authorizeComment(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID {
+		if err := s.AuthorizeProject(interfaceToString(task.ProjectID), orgID, userID, models.ProjectRoleLead); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteComment(comment)
+}
+
+// GetCommentHistory lists the earlier bodies of a comment, newest first
+func (s *taskService) GetCommentHistory(id string, orgID string, userID uint) ([]CommentRevision, error) {
+	comment, _, err := s.authorizeComment(id, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := s.repo.FindCommentRevisions(comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revisions == nil {
+		revisions = []CommentRevision{}
+	}
+	return revisions, nil
+}
+
+// Helper: tells the watchers of a task about a new comment. Mentioned users and the
+// author of the parent comment get the more specific notification instead.
+func (s *taskService) notifyComment(orgID string, task *Task, comment *Comment, parent *Comment) {
+	recipients := s.recipients(task)
+
+	mentioned := onlyRecipients(comment.MentionIDs, recipients)
+	s.notifyMentions(orgID, task, comment, mentioned)
+	notified := append([]uint{comment.AuthorID}, mentioned...)
+
+	if parent != nil && containsUint(recipients, parent.AuthorID) && !containsUint(notified, parent.AuthorID) {
+		s.notify(orgID, []uint{parent.AuthorID}, notifications.Notification{
+			Type:      notifications.TypeReply,
+			ActorID:   comment.AuthorID,
+			TaskID:    &task.ID,
+			CommentID: &comment.ID,
+			Message:   "New reply to your comment on " + task.Key,
+		})
+		notified = append(notified, parent.AuthorID)
+	}
+
+	var others []uint
+	for _, id := range recipients {
+		if !containsUint(notified, id) {
+			others = append(others, id)
+		}
+	}
+	s.notify(orgID, others, notifications.Notification{
+		Type:      notifications.TypeComment,
+		ActorID:   comment.AuthorID,
+		TaskID:    &task.ID,
+		CommentID: &comment.ID,
+		Message:   "New comment on " + task.Key,
+	})
+}
+
+// Helper: tells the users mentioned in a comment about it
+func (s *taskService) notifyMentions(orgID string, task *Task, comment *Comment, userIDs []uint) {
+	s.notify(orgID, userIDs, notifications.Notification{
+		Type:      notifications.TypeMention,
+		ActorID:   comment.AuthorID,
+		TaskID:    &task.ID,
+		CommentID: &comment.ID,
+		Message:   "You were mentioned in a comment on " + task.Key,
+	})
+}
+
+// Helper: the comment is already saved, a failed notification must not undo it
+func (s *taskService) notify(orgID string, userIDs []uint, notification notifications.Notification) {
+	if len(userIDs) == 0 {
+		return
+	}
+	if err := s.notifier.Notify(orgID, userIDs, notification); err != nil {
+		log.Printf("notify %s on comment %d failed: %v", notification.Type, *notification.CommentID, err)
+	}
+}
@@ -0,0 +1,117 @@
+package tasks
+
+import (
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /projects/:id/custom-fields
+func (h *Handler) FindCustomFields(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	fields, err := h.service.GetCustomFields(projectID, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch custom fields")
+		return
+	}
+
+	utils.SendSuccess(c, "success", fields)
+}
+
+// POST /projects/:id/custom-fields
+func (h *Handler) CreateCustomField(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, ErrProjectNotFound.Error())
+		return
+	}
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name     string   `json:"name" binding:"required"`
+		Type     string   `json:"type" binding:"required"`
+		Options  []string `json:"options"`
+		Required bool     `json:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	field, err := h.service.CreateCustomField(uint(projectID), orgID, user.ID, CustomFieldInput{
+		Name:     req.Name,
+		Type:     req.Type,
+		Options:  req.Options,
+		Required: req.Required,
+	})
+	if err != nil {
+		sendTaskError(c, err, "Failed to create custom field")
+		return
+	}
+
+	utils.SendSuccess(c, "Custom field created", field)
+}
+
+// PATCH /custom-fields/:id
+func (h *Handler) UpdateCustomField(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name     *string  `json:"name"`
+		Options  []string `json:"options"`
+		Required *bool    `json:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	field, err := h.service.UpdateCustomField(id, orgID, user.ID, UpdateCustomFieldInput{
+		Name:     req.Name,
+		Options:  req.Options,
+		Required: req.Required,
+	})
+	if err != nil {
+		sendTaskError(c, err, "Failed to update custom field")
+		return
+	}
+
+	utils.SendSuccess(c, "Custom field updated", field)
+}
+
+// DELETE /custom-fields/:id
+func (h *Handler) DeleteCustomField(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteCustomField(id, orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to delete custom field")
+		return
+	}
+
+	utils.SendSuccess(c, "Custom field deleted")
+}
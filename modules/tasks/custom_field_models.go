@@ -0,0 +1,35 @@
+package tasks
+
+import "time"
+
+// Custom field types
+const (
+	CustomFieldText         = "text"
+	CustomFieldNumber       = "number"
+	CustomFieldDate         = "date" // stored as "2006-01-02"
+	CustomFieldSingleSelect = "single_select"
+	CustomFieldMultiSelect  = "multi_select"
+	CustomFieldUser         = "user" // stored as the user ID
+)
+
+// CustomField is an extra task attribute defined by a project
+type CustomField struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProjectID uint      `gorm:"index" json:"project_id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Options   []string  `gorm:"serializer:json" json:"options"` // choices of the select types
+	Required  bool      `json:"required"`
+	Index     int       `json:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CustomFieldValues maps a custom field ID (as a string, e.g. "12") to the task's value
+type CustomFieldValues map[string]interface{}
+
+// CustomFieldFilter is one "cf.<id>" condition of the task list, already checked against the field type
+type CustomFieldFilter struct {
+	Field CustomField
+	Op    string // "eq", "min" or "max"
+	Value interface{}
+}
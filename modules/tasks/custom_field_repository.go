@@ -0,0 +1,100 @@
+package tasks
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) CreateCustomField(field *CustomField) error {
+	return r.db.Create(field).Error
+}
+
+func (r *repository) FindCustomFields(projectID uint) ([]CustomField, error) {
+	var fields []CustomField
+	err := r.db.Where("project_id = ?", projectID).Order("index asc, id asc").Find(&fields).Error
+	return fields, err
+}
+
+func (r *repository) FindCustomFieldByID(id string) (*CustomField, error) {
+	var field CustomField
+	err := r.db.Where("id = ?", id).First(&field).Error
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (r *repository) UpdateCustomField(field *CustomField, updates map[string]interface{}) error {
+	return r.db.Model(field).Updates(updates).Error
+}
+
+// DeleteCustomField strips the field's values from every task of the project (trashed ones included)
+func (r *repository) DeleteCustomField(field *CustomField) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&Task{}).
+			Where("project_id = ? AND jsonb_typeof(custom_fields) = 'object'", field.ProjectID).
+			Update("custom_fields", gorm.Expr("custom_fields - CAST(? AS text)", fmt.Sprint(field.ID))).Error; err != nil {
+			return err
+		}
+		return tx.Delete(field).Error
+	})
+}
+
+// CountTasksWithOption counts tasks whose select field holds option
+func (r *repository) CountTasksWithOption(field *CustomField, option string) (int64, error) {
+	var count int64
+	query := r.db.Unscoped().Model(&Task{}).Where("project_id = ?", field.ProjectID)
+	if field.Type == CustomFieldMultiSelect {
+		query = query.Where(fmt.Sprintf("custom_fields->'%d' @> to_jsonb(CAST(? AS text))", field.ID), option)
+	} else {
+		query = query.Where(fmt.Sprintf("custom_fields->>'%d' = ?", field.ID), option)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// Helper: applies the "cf.<id>" filters of the task list
+func applyCustomFieldFilters(query *gorm.DB, filters []CustomFieldFilter) *gorm.DB {
+	for _, f := range filters {
+		text := fmt.Sprintf("(tasks.custom_fields->>'%d')", f.Field.ID)
+		numeric := fmt.Sprintf("CAST(tasks.custom_fields->>'%d' AS numeric)", f.Field.ID)
+
+		switch f.Field.Type {
+		case CustomFieldText:
+			query = query.Where(text+" ILIKE ?", f.Value)
+		case CustomFieldNumber, CustomFieldUser:
+			query = query.Where(numeric+" "+filterOperator(f.Op)+" ?", f.Value)
+		case CustomFieldDate:
+			query = query.Where(text+" "+filterOperator(f.Op)+" ?", f.Value)
+		case CustomFieldSingleSelect:
+			query = query.Where(text+" = ?", f.Value)
+		case CustomFieldMultiSelect:
+			query = query.Where(fmt.Sprintf("tasks.custom_fields->'%d' @> to_jsonb(CAST(? AS text))", f.Field.ID), f.Value)
+		}
+	}
+	return query
+}
+
+// Helper: SQL ordering on a custom field, tasks without a value go last
+func customFieldOrder(field *CustomField, desc bool) string {
+	column := fmt.Sprintf("(tasks.custom_fields->>'%d')", field.ID)
+	if field.Type == CustomFieldNumber || field.Type == CustomFieldUser {
+		column = fmt.Sprintf("CAST(tasks.custom_fields->>'%d' AS numeric)", field.ID)
+	}
+	if desc {
+		return column + " DESC NULLS LAST"
+	}
+	return column + " ASC NULLS LAST"
+}
+
+func filterOperator(op string) string {
+	switch op {
+	case "min":
+		return ">="
+	case "max":
+		return "<="
+	default:
+		return "="
+	}
+}
@@ -0,0 +1,396 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gotask-backend/models"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+	ErrInvalidCustomField  = errors.New("invalid custom field")
+)
+
+// IsValidCustomFieldType checks a field type coming from user input
+func IsValidCustomFieldType(fieldType string) bool {
+	switch fieldType {
+	case CustomFieldText, CustomFieldNumber, CustomFieldDate,
+		CustomFieldSingleSelect, CustomFieldMultiSelect, CustomFieldUser:
+		return true
+	}
+	return false
+}
+
+// Input DTO
+type CustomFieldInput struct {
+	Name     string
+	Type     string
+	Options  []string
+	Required bool
+}
+
+type UpdateCustomFieldInput struct {
+	Name     *string
+	Options  []string
+	Required *bool
+}
+
+// Helper: wraps a validation message so handlers can map it to a 400
+func invalidCustomField(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidCustomField, fmt.Sprintf(format, args...))
+}
+
+// ValidateCustomFieldDefinition checks name, type and options; select types need
+// unique, non-empty options and the others take none. Shared with project templates.
+func ValidateCustomFieldDefinition(name string, fieldType string, options []string) error {
+	if strings.TrimSpace(name) == "" {
+		return invalidCustomField("name is required")
+	}
+	if !IsValidCustomFieldType(fieldType) {
+		return invalidCustomField("type must be one of text, number, date, single_select, multi_select, user")
+	}
+
+	isSelect := fieldType == CustomFieldSingleSelect || fieldType == CustomFieldMultiSelect
+	if !isSelect {
+		if len(options) > 0 {
+			return invalidCustomField("only select fields take options")
+		}
+		return nil
+	}
+	if len(options) == 0 {
+		return invalidCustomField("select fields need at least one option")
+	}
+	seen := make(map[string]bool)
+	for _, o := range options {
+		if o == "" || seen[o] {
+			return invalidCustomField("options must be unique and non-empty")
+		}
+		seen[o] = true
+	}
+	return nil
+}
+
+// Helper: loads a custom field and checks the caller's role on its project
+func (s *taskService) authorizeCustomField(id string, orgID string, userID uint, minRole string) (*CustomField, error) {
+	field, err := s.repo.FindCustomFieldByID(id)
+	if err != nil {
+		return nil, ErrCustomFieldNotFound
+	}
+	err = s.AuthorizeProject(interfaceToString(field.ProjectID), orgID, userID, minRole)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil, ErrCustomFieldNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+func (s *taskService) GetCustomFields(projectID string, orgID string, userID uint) ([]CustomField, error) {
+	if err := s.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(projectID, 10, 64)
+	if err != nil {
+		return nil, ErrProjectNotFound
+	}
+	return s.repo.FindCustomFields(uint(id))
+}
+
+func (s *taskService) CreateCustomField(projectID uint, orgID string, userID uint, input CustomFieldInput) (*CustomField, error) {
+	if err := s.AuthorizeProject(interfaceToString(projectID), orgID, userID, models.ProjectRoleLead); err != nil {
+		return nil, err
+	}
+	if err := ValidateCustomFieldDefinition(input.Name, input.Type, input.Options); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindCustomFields(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range existing {
+		if strings.EqualFold(f.Name, input.Name) {
+			return nil, invalidCustomField("a field named '%s' already exists", input.Name)
+		}
+	}
+
+	field := CustomField{
+		ProjectID: projectID,
+		Name:      input.Name,
+		Type:      input.Type,
+		Options:   input.Options,
+		Required:  input.Required,
+		Index:     len(existing),
+	}
+	if err := s.repo.CreateCustomField(&field); err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// UpdateCustomField can rename a field, change its options and toggle required; the type is fixed.
+// Options still used by tasks cannot be removed.
+func (s *taskService) UpdateCustomField(id string, orgID string, userID uint, input UpdateCustomFieldInput) (*CustomField, error) {
+	field, err := s.authorizeCustomField(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	name, options := field.Name, field.Options
+	if input.Name != nil {
+		name = *input.Name
+		updates["name"] = name
+	}
+	if input.Options != nil {
+		options = input.Options
+		kept := make(map[string]bool)
+		for _, o := range options {
+			kept[o] = true
+		}
+		for _, old := range field.Options {
+			if kept[old] {
+				continue
+			}
+			count, err := s.repo.CountTasksWithOption(field, old)
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				return nil, invalidCustomField("option '%s' is still used by %d task(s)", old, count)
+			}
+		}
+		// Map updates skip the serializer tag, so the column gets the JSON itself
+		encoded, err := json.Marshal(options)
+		if err != nil {
+			return nil, err
+		}
+		updates["options"] = string(encoded)
+	}
+	if input.Required != nil {
+		updates["required"] = *input.Required
+	}
+
+	if err := ValidateCustomFieldDefinition(name, field.Type, options); err != nil {
+		return nil, err
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.UpdateCustomField(field, updates); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindCustomFieldByID(id)
+}
+
+func (s *taskService) DeleteCustomField(id string, orgID string, userID uint) error {
+	field, err := s.authorizeCustomField(id, orgID, userID, models.ProjectRoleLead)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteCustomField(field)
+}
+
+// Helper: checks incoming values against the project's fields and merges them into current.
+// A null value clears the field. Required fields are enforced on create and when cleared.
+func (s *taskService) mergeCustomFields(projectID uint, current CustomFieldValues, input map[string]interface{}, creating bool) (CustomFieldValues, error) {
+	fields, err := s.repo.FindCustomFields(projectID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]CustomField, len(fields))
+	for _, f := range fields {
+		byID[interfaceToString(f.ID)] = f
+	}
+
+	// Values of deleted fields are dropped along the way
+	result := make(CustomFieldValues)
+	for key, value := range current {
+		if _, ok := byID[key]; ok {
+			result[key] = value
+		}
+	}
+
+	var userIDs []uint
+	for key, raw := range input {
+		field, ok := byID[key]
+		if !ok {
+			return nil, invalidCustomField("unknown custom field '%s'", key)
+		}
+		if raw == nil {
+			if field.Required {
+				return nil, invalidCustomField("'%s' is required", field.Name)
+			}
+			delete(result, key)
+			continue
+		}
+
+		value, err := normalizeCustomValue(field, raw)
+		if err != nil {
+			return nil, err
+		}
+		if field.Type == CustomFieldUser {
+			userIDs = append(userIDs, value.(uint))
+		}
+		result[key] = value
+	}
+
+	if creating {
+		for key, field := range byID {
+			if _, ok := result[key]; field.Required && !ok {
+				return nil, invalidCustomField("'%s' is required", field.Name)
+			}
+		}
+	}
+
+	if len(userIDs) > 0 {
+		members, err := s.repo.FilterProjectMembers(projectID, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) != len(uniqueUints(userIDs)) {
+			return nil, invalidCustomField("user fields only accept members of the project")
+		}
+	}
+
+	return result, nil
+}
+
+// Helper: type-checks one value and returns it in its stored form
+func normalizeCustomValue(field CustomField, raw interface{}) (interface{}, error) {
+	switch field.Type {
+	case CustomFieldText:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, invalidCustomField("'%s' must be a string", field.Name)
+		}
+		return text, nil
+
+	case CustomFieldNumber:
+		number, ok := raw.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, invalidCustomField("'%s' must be a number", field.Name)
+		}
+		return number, nil
+
+	case CustomFieldDate:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, invalidCustomField("'%s' must be a date like 2024-01-31", field.Name)
+		}
+		date, err := time.Parse("2006-01-02", text)
+		if err != nil {
+			return nil, invalidCustomField("'%s' must be a date like 2024-01-31", field.Name)
+		}
+		return date.Format("2006-01-02"), nil
+
+	case CustomFieldSingleSelect:
+		text, ok := raw.(string)
+		if !ok || !containsString(field.Options, text) {
+			return nil, invalidCustomField("'%s' must be one of %s", field.Name, strings.Join(field.Options, ", "))
+		}
+		return text, nil
+
+	case CustomFieldMultiSelect:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return nil, invalidCustomField("'%s' must be a list of options", field.Name)
+		}
+		chosen := make([]string, 0, len(items))
+		for _, item := range items {
+			text, ok := item.(string)
+			if !ok || !containsString(field.Options, text) {
+				return nil, invalidCustomField("'%s' accepts only %s", field.Name, strings.Join(field.Options, ", "))
+			}
+			if !containsString(chosen, text) {
+				chosen = append(chosen, text)
+			}
+		}
+		return chosen, nil
+
+	case CustomFieldUser:
+		number, ok := raw.(float64)
+		if !ok || number <= 0 || number != math.Trunc(number) {
+			return nil, invalidCustomField("'%s' must be a user ID", field.Name)
+		}
+		return uint(number), nil
+	}
+	return nil, invalidCustomField("'%s' has an unknown type", field.Name)
+}
+
+// ParseCustomFieldFilter turns a "cf.<id>[.min|.max]" query parameter into a filter on one of the fields
+func ParseCustomFieldFilter(fields []CustomField, key string, value string) (*CustomFieldFilter, error) {
+	parts := strings.SplitN(key, ".", 2)
+	op := "eq"
+	if len(parts) == 2 {
+		op = parts[1]
+	}
+
+	var field *CustomField
+	for i := range fields {
+		if interfaceToString(fields[i].ID) == parts[0] {
+			field = &fields[i]
+			break
+		}
+	}
+	if field == nil {
+		return nil, invalidCustomField("unknown custom field '%s'", parts[0])
+	}
+
+	ranged := field.Type == CustomFieldNumber || field.Type == CustomFieldDate
+	if op != "eq" && !(ranged && (op == "min" || op == "max")) {
+		return nil, invalidCustomField("'%s' cannot be filtered with .%s", field.Name, op)
+	}
+
+	filter := CustomFieldFilter{Field: *field, Op: op}
+	switch field.Type {
+	case CustomFieldText:
+		filter.Value = "%" + escapeLike(value) + "%"
+	case CustomFieldNumber, CustomFieldUser:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, invalidCustomField("'%s' filter must be a number", field.Name)
+		}
+		filter.Value = number
+	case CustomFieldDate:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return nil, invalidCustomField("'%s' filter must be a date like 2024-01-31", field.Name)
+		}
+		filter.Value = value
+	default:
+		filter.Value = value
+	}
+	return &filter, nil
+}
+
+// Helper: escapes LIKE wildcards in user input
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueUints(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
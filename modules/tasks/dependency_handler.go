@@ -0,0 +1,93 @@
+package tasks
+
+import (
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /tasks/:id/dependencies
+func (h *Handler) GetDependencies(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	dependencies, err := h.service.GetDependencies(ref, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch dependencies")
+		return
+	}
+
+	utils.SendSuccess(c, "success", dependencies)
+}
+
+// POST /tasks/:id/dependencies {"blocks": "WEB-7"} or {"blocked_by": "12"}
+func (h *Handler) AddDependency(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Blocks    string `json:"blocks"`
+		BlockedBy string `json:"blocked_by"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dependencies, err := h.service.AddDependency(ref, orgID, user.ID, DependencyInput{
+		Blocks:    req.Blocks,
+		BlockedBy: req.BlockedBy,
+	})
+	if err != nil {
+		sendTaskError(c, err, "Failed to link tasks")
+		return
+	}
+
+	utils.SendSuccess(c, "Tasks linked", dependencies)
+}
+
+// DELETE /tasks/:id/dependencies/:otherId
+func (h *Handler) RemoveDependency(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.RemoveDependency(ref, c.Param("otherId"), orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to unlink tasks")
+		return
+	}
+
+	utils.SendSuccess(c, "Tasks unlinked")
+}
+
+// GET /projects/:id/dependencies
+func (h *Handler) GetDependencyGraph(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	graph, err := h.service.GetDependencyGraph(projectID, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch dependency graph")
+		return
+	}
+
+	utils.SendSuccess(c, "success", graph)
+}
@@ -0,0 +1,40 @@
+package tasks
+
+import "time"
+
+// TaskDependency says BlockerID has to be finished before BlockedID can be.
+// Both tasks belong to the same organization but may sit in different projects.
+type TaskDependency struct {
+	BlockerID uint      `gorm:"primaryKey" json:"blocker_id"`
+	BlockedID uint      `gorm:"primaryKey;index" json:"blocked_id"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaskDependencies lists the live tasks on both sides of a task that the caller can see
+type TaskDependencies struct {
+	Blocks    []Task `json:"blocks"`
+	BlockedBy []Task `json:"blocked_by"`
+}
+
+// DependencyNode is one task of a dependency graph
+type DependencyNode struct {
+	ID             uint   `json:"id"`
+	Key            string `json:"key"`
+	Title          string `json:"title"`
+	ProjectID      uint   `json:"project_id"`
+	StatusID       uint   `json:"status_id"`
+	StatusCategory string `json:"status_category"`
+}
+
+type DependencyEdge struct {
+	BlockerID uint `json:"blocker_id"`
+	BlockedID uint `json:"blocked_id"`
+}
+
+// DependencyGraph holds every link touching a project's tasks; tasks of other
+// projects show up as nodes too when the caller can see them
+type DependencyGraph struct {
+	Nodes []DependencyNode `json:"nodes"`
+	Edges []DependencyEdge `json:"edges"`
+}
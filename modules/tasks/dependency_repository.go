@@ -0,0 +1,159 @@
+package tasks
+
+import (
+	"gotask-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateDependency reports false when the link already exists and fails with
+// ErrDependencyCycle when the blocked task already leads back to the blocker. The
+// organization row is locked while checking, so two links added at the same time
+// cannot close a cycle together (links never leave an organization).
+func (r *repository) CreateDependency(dependency *TaskDependency, orgID string) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT id FROM organizations WHERE id = ? FOR UPDATE", orgID).Error; err != nil {
+			return err
+		}
+		cycle, err := dependencyPathExists(tx, dependency.BlockedID, dependency.BlockerID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(dependency)
+		created = result.RowsAffected > 0
+		return result.Error
+	})
+	return created, err
+}
+
+// DeleteDependency removes the link between two tasks, whichever way it points
+func (r *repository) DeleteDependency(taskID uint, otherID uint) (int64, error) {
+	result := r.db.
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", taskID, otherID, otherID, taskID).
+		Delete(&TaskDependency{})
+	return result.RowsAffected, result.Error
+}
+
+// Helper: reports whether "from" already blocks "to", directly or through other tasks
+func dependencyPathExists(tx *gorm.DB, from uint, to uint) (bool, error) {
+	var found []uint
+	err := tx.Raw(`
+		WITH RECURSIVE downstream AS (
+			SELECT blocked_id FROM task_dependencies WHERE blocker_id = ?
+			UNION
+			SELECT task_dependencies.blocked_id FROM task_dependencies
+			JOIN downstream ON task_dependencies.blocker_id = downstream.blocked_id
+		)
+		SELECT blocked_id FROM downstream WHERE blocked_id = ? LIMIT 1`, from, to).
+		Scan(&found).Error
+	return len(found) > 0, err
+}
+
+// Helper: live tasks of the org the user can see
+func (r *repository) visibleTasks(orgID string, userID uint) *gorm.DB {
+	return r.db.Model(&Task{}).
+		Joins("JOIN projects ON projects.id = tasks.project_id AND projects.deleted_at IS NULL").
+		Where("projects.organization_id = ?", orgID).
+		Scopes(models.VisibleToUser(userID))
+}
+
+// FindBlockers returns the tasks that block taskID
+func (r *repository) FindBlockers(taskID uint, orgID string, userID uint) ([]Task, error) {
+	var blockers []Task
+	err := r.visibleTasks(orgID, userID).
+		Preload("Status").
+		Preload("Priority").
+		Where("tasks.id IN (SELECT blocker_id FROM task_dependencies WHERE blocked_id = ?)", taskID).
+		Order("tasks.id asc").
+		Find(&blockers).Error
+	if err != nil {
+		return nil, err
+	}
+	_ = r.fillKeys(blockers)
+	return blockers, nil
+}
+
+// FindBlockedTasks returns the tasks that taskID blocks
+func (r *repository) FindBlockedTasks(taskID uint, orgID string, userID uint) ([]Task, error) {
+	var blocked []Task
+	err := r.visibleTasks(orgID, userID).
+		Preload("Status").
+		Preload("Priority").
+		Where("tasks.id IN (SELECT blocked_id FROM task_dependencies WHERE blocker_id = ?)", taskID).
+		Order("tasks.id asc").
+		Find(&blocked).Error
+	if err != nil {
+		return nil, err
+	}
+	_ = r.fillKeys(blocked)
+	return blocked, nil
+}
+
+// CountOpenBlockers counts the live blockers of a task that are not in a "done" status,
+// among the tasks the user can see
+func (r *repository) CountOpenBlockers(taskID uint, orgID string, userID uint) (int64, error) {
+	var count int64
+	err := r.visibleTasks(orgID, userID).
+		Joins("JOIN task_dependencies ON task_dependencies.blocker_id = tasks.id").
+		Joins("JOIN statuses ON statuses.id = tasks.status_id").
+		Where("task_dependencies.blocked_id = ? AND statuses.category <> ?", taskID, StatusCategoryDone).
+		Count(&count).Error
+	return count, err
+}
+
+// FindDependencyGraph collects the links touching a project's live tasks, keeping
+// only those whose both ends the user can see
+func (r *repository) FindDependencyGraph(projectID uint, orgID string, userID uint) (*DependencyGraph, error) {
+	graph := DependencyGraph{Nodes: []DependencyNode{}, Edges: []DependencyEdge{}}
+
+	var edges []DependencyEdge
+	err := r.db.Table("task_dependencies").
+		Select("task_dependencies.blocker_id, task_dependencies.blocked_id").
+		Joins("JOIN tasks blocker ON blocker.id = task_dependencies.blocker_id AND blocker.deleted_at IS NULL").
+		Joins("JOIN tasks blocked ON blocked.id = task_dependencies.blocked_id AND blocked.deleted_at IS NULL").
+		Where("blocker.project_id = ? OR blocked.project_id = ?", projectID, projectID).
+		Order("task_dependencies.blocker_id, task_dependencies.blocked_id").
+		Scan(&edges).Error
+	if err != nil || len(edges) == 0 {
+		return &graph, err
+	}
+
+	ids := make([]uint, 0, len(edges)*2)
+	for _, e := range edges {
+		ids = append(ids, e.BlockerID, e.BlockedID)
+	}
+	var nodes []DependencyNode
+	err = r.visibleTasks(orgID, userID).
+		Select(`tasks.id, projects.key || '-' || tasks.number AS key, tasks.title, tasks.project_id,
+			tasks.status_id, statuses.category AS status_category`).
+		Joins("JOIN statuses ON statuses.id = tasks.status_id").
+		Where("tasks.id IN ?", uniqueUints(ids)).
+		Order("tasks.id asc").
+		Scan(&nodes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[uint]bool)
+	for _, n := range nodes {
+		visible[n.ID] = true
+	}
+	linked := make(map[uint]bool)
+	for _, e := range edges {
+		if visible[e.BlockerID] && visible[e.BlockedID] {
+			graph.Edges = append(graph.Edges, e)
+			linked[e.BlockerID], linked[e.BlockedID] = true, true
+		}
+	}
+	for _, n := range nodes {
+		if linked[n.ID] {
+			graph.Nodes = append(graph.Nodes, n)
+		}
+	}
+	return &graph, nil
+}
@@ -0,0 +1,128 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"gotask-backend/models"
+	"strconv"
+)
+
+var (
+	ErrInvalidDependency  = errors.New("give exactly one of 'blocks' or 'blocked_by'")
+	ErrDependencyCycle    = errors.New("this link would make the tasks block each other")
+	ErrDependencyExists   = errors.New("the tasks are already linked")
+	ErrDependencyNotFound = errors.New("the tasks are not linked")
+	ErrTaskBlocked        = errors.New("task is blocked by unfinished tasks")
+)
+
+// DependencyInput names the other task of a new link by ID or key
+type DependencyInput struct {
+	Blocks    string // the other task waits for this one
+	BlockedBy string // this task waits for the other one
+}
+
+func (s *taskService) GetDependencies(ref string, orgID string, userID uint) (*TaskDependencies, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := s.repo.FindBlockedTasks(task.ID, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	blockedBy, err := s.repo.FindBlockers(task.ID, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskDependencies{Blocks: blocks, BlockedBy: blockedBy}, nil
+}
+
+// AddDependency links two tasks of the org. Editing the task's links needs contributor
+// rights on it; the other task only has to be visible to the caller.
+func (s *taskService) AddDependency(ref string, orgID string, userID uint, input DependencyInput) (*TaskDependencies, error) {
+	if (input.Blocks == "") == (input.BlockedBy == "") {
+		return nil, ErrInvalidDependency
+	}
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+
+	otherRef := input.Blocks
+	if otherRef == "" {
+		otherRef = input.BlockedBy
+	}
+	other, err := s.authorizeTask(otherRef, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	dependency := TaskDependency{BlockerID: other.ID, BlockedID: task.ID, CreatedBy: userID}
+	if input.Blocks != "" {
+		dependency = TaskDependency{BlockerID: task.ID, BlockedID: other.ID, CreatedBy: userID}
+	}
+
+	// A cycle exists if the blocked task already leads back to the blocker
+	if dependency.BlockerID == dependency.BlockedID {
+		return nil, ErrDependencyCycle
+	}
+	created, err := s.repo.CreateDependency(&dependency, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrDependencyExists
+	}
+	return s.GetDependencies(interfaceToString(task.ID), orgID, userID)
+}
+
+func (s *taskService) RemoveDependency(ref string, otherRef string, orgID string, userID uint) error {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return err
+	}
+
+	// The other task may be gone or out of sight; a numeric ID still unlinks it
+	var otherID uint
+	if other, err := s.findTaskByRef(otherRef, orgID); err == nil {
+		otherID = other.ID
+	} else if id, err := strconv.ParseUint(otherRef, 10, 64); err == nil {
+		otherID = uint(id)
+	} else {
+		return ErrDependencyNotFound
+	}
+
+	removed, err := s.repo.DeleteDependency(task.ID, otherID)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrDependencyNotFound
+	}
+	return nil
+}
+
+func (s *taskService) GetDependencyGraph(projectID string, orgID string, userID uint) (*DependencyGraph, error) {
+	if err := s.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(projectID, 10, 64)
+	if err != nil {
+		return nil, ErrProjectNotFound
+	}
+	return s.repo.FindDependencyGraph(uint(id), orgID, userID)
+}
+
+// Helper: a task cannot be finished while tasks blocking it are still open; blockers the
+// user cannot see do not count, as they are not listed to them either
+func (s *taskService) checkBlockers(taskID uint, orgID string, userID uint) error {
+	open, err := s.repo.CountOpenBlockers(taskID, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return fmt.Errorf("%w (%d still open), set ignore_blockers to finish it anyway", ErrTaskBlocked, open)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package tasks
+
+import (
+	"gotask-backend/models"
+	"gotask-backend/modules/auth"
+)
+
+// FindMentionedUsers returns members of the org whose email, or the part of it before
+// the "@", matches one of the tokens (case-insensitive)
+func (r *repository) FindMentionedUsers(orgID string, tokens []string) ([]auth.User, error) {
+	var users []auth.User
+	if len(tokens) == 0 {
+		return users, nil
+	}
+	err := r.db.Table("users").
+		Select("users.id, users.email").
+		Joins("JOIN organization_users ON organization_users.user_id = users.id AND organization_users.organization_id = ?", orgID).
+		Where("LOWER(users.email) IN ? OR LOWER(SPLIT_PART(users.email, '@', 1)) IN ?", tokens, tokens).
+		Scan(&users).Error
+	return users, err
+}
+
+// FindVisibleTaskKeys keeps the keys of live tasks in the org's projects the user can see
+func (r *repository) FindVisibleTaskKeys(orgID string, userID uint, keys []string) ([]string, error) {
+	var found []string
+	if len(keys) == 0 {
+		return found, nil
+	}
+	err := r.db.Table("tasks").
+		Joins("JOIN projects ON projects.id = tasks.project_id").
+		Where("projects.organization_id = ? AND projects.deleted_at IS NULL AND tasks.deleted_at IS NULL", orgID).
+		Where("(projects.key || '-' || tasks.number) IN ?", keys).
+		Scopes(models.VisibleToUser(userID)).
+		Pluck("projects.key || '-' || tasks.number", &found).Error
+	return found, err
+}
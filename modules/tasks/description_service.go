@@ -0,0 +1,132 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"gotask-backend/utils"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrDescriptionTooLong = errors.New("description must be at most 50000 characters")
+
+// MaxDescriptionLength caps task descriptions, in characters
+const MaxDescriptionLength = 50000
+
+// renderedMarkdown is what gets stored next to a Markdown source
+type renderedMarkdown struct {
+	HTML           string
+	MentionIDs     []uint
+	ReferencedKeys []string
+}
+
+// Helper: checks the length of a description and renders it
+func (s *taskService) renderDescription(projectID uint, orgID string, userID uint, source string) (*renderedMarkdown, error) {
+	if utf8.RuneCountInString(source) > MaxDescriptionLength {
+		return nil, ErrDescriptionTooLong
+	}
+	return s.renderMarkdown(projectID, orgID, userID, source)
+}
+
+// Helper: renders task Markdown (descriptions, comments) to sanitized HTML. Mentions
+// resolve to members of the task's project, by email or by the part of it before the "@"
+// when that is unique; references resolve to tasks of the org the author can see.
+// Anything else stays text.
+func (s *taskService) renderMarkdown(projectID uint, orgID string, userID uint, source string) (*renderedMarkdown, error) {
+	mentions, refs := utils.MarkdownTokens(source)
+
+	for i := range mentions {
+		mentions[i] = strings.ToLower(mentions[i])
+	}
+	users, err := s.repo.FindMentionedUsers(orgID, mentions)
+	if err != nil {
+		return nil, err
+	}
+	var candidateIDs []uint
+	for _, u := range users {
+		candidateIDs = append(candidateIDs, u.ID)
+	}
+	memberIDs, err := s.repo.FilterProjectMembers(projectID, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Full emails win; a local part only counts when a single member has it
+	byEmail := make(map[string]uint)
+	byLocal := make(map[string][]uint)
+	emails := make(map[uint]string)
+	for _, u := range users {
+		if !containsUint(memberIDs, u.ID) {
+			continue
+		}
+		email := strings.ToLower(u.Email)
+		byEmail[email] = u.ID
+		local, _, _ := strings.Cut(email, "@")
+		byLocal[local] = append(byLocal[local], u.ID)
+		emails[u.ID] = u.Email
+	}
+	resolve := func(token string) (uint, bool) {
+		token = strings.ToLower(token)
+		if id, ok := byEmail[token]; ok {
+			return id, true
+		}
+		if ids := byLocal[token]; len(ids) == 1 {
+			return ids[0], true
+		}
+		return 0, false
+	}
+
+	keys, err := s.repo.FindVisibleTaskKeys(orgID, userID, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := renderedMarkdown{MentionIDs: []uint{}, ReferencedKeys: []string{}}
+	for _, m := range mentions {
+		if id, ok := resolve(m); ok && !containsUint(rendered.MentionIDs, id) {
+			rendered.MentionIDs = append(rendered.MentionIDs, id)
+		}
+	}
+	for _, ref := range refs {
+		if containsString(keys, ref) {
+			rendered.ReferencedKeys = append(rendered.ReferencedKeys, ref)
+		}
+	}
+
+	rendered.HTML = utils.RenderMarkdown(source, func(kind string, token string) (string, bool) {
+		if kind == utils.MarkdownMention {
+			id, ok := resolve(token)
+			return "mailto:" + emails[id], ok
+		}
+		return "/tasks/" + token, containsString(keys, token)
+	})
+	return &rendered, nil
+}
+
+// Helper: the column updates for a new description; map updates skip the serializer
+// tag, so the JSON columns get the encoded JSON itself
+func descriptionUpdates(source string, rendered *renderedMarkdown) (map[string]interface{}, error) {
+	mentionIDs, err := json.Marshal(rendered.MentionIDs)
+	if err != nil {
+		return nil, err
+	}
+	referencedKeys, err := json.Marshal(rendered.ReferencedKeys)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"description":      source,
+		"description_html": rendered.HTML,
+		"mention_ids":      string(mentionIDs),
+		"referenced_keys":  string(referencedKeys),
+	}, nil
+}
+
+func containsUint(list []uint, value uint) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package tasks
+
+import (
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /labels (organization labels)
+func (h *Handler) FindLabels(c *gin.Context) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	labels, err := h.service.GetLabels("", orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch labels")
+		return
+	}
+
+	utils.SendSuccess(c, "success", labels)
+}
+
+// GET /projects/:id/labels (organization labels and the project's own)
+func (h *Handler) FindProjectLabels(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	labels, err := h.service.GetLabels(projectID, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch labels")
+		return
+	}
+
+	utils.SendSuccess(c, "success", labels)
+}
+
+// POST /labels {"name": "bug", "color": "#DC2626"} (organization label, admins only)
+func (h *Handler) CreateLabel(c *gin.Context) {
+	h.createLabel(c, nil)
+}
+
+// POST /projects/:id/labels {"name": "bug", "color": "#DC2626"} (project label, leads only)
+func (h *Handler) CreateProjectLabel(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		sendTaskError(c, ErrProjectNotFound, "")
+		return
+	}
+	id := uint(projectID)
+	h.createLabel(c, &id)
+}
+
+func (h *Handler) createLabel(c *gin.Context, projectID *uint) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name  string `json:"name" binding:"required"`
+		Color string `json:"color"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	label, err := h.service.CreateLabel(orgID, user.ID, LabelInput{
+		ProjectID: projectID,
+		Name:      req.Name,
+		Color:     req.Color,
+	})
+	if err != nil {
+		sendTaskError(c, err, "Failed to create label")
+		return
+	}
+
+	utils.SendSuccess(c, "Label created", label)
+}
+
+// PATCH /labels/:id {"name", "color"}
+func (h *Handler) UpdateLabel(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Name  *string `json:"name"`
+		Color *string `json:"color"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	label, err := h.service.UpdateLabel(id, orgID, user.ID, UpdateLabelInput{Name: req.Name, Color: req.Color})
+	if err != nil {
+		sendTaskError(c, err, "Failed to update label")
+		return
+	}
+
+	utils.SendSuccess(c, "Label updated", label)
+}
+
+// DELETE /labels/:id
+func (h *Handler) DeleteLabel(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteLabel(id, orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to delete label")
+		return
+	}
+
+	utils.SendSuccess(c, "Label deleted")
+}
+
+// POST /labels/:id/merge {"into": 7} moves every task to label 7 and deletes label :id
+func (h *Handler) MergeLabels(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Into uint `json:"into" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	label, moved, err := h.service.MergeLabels(id, interfaceToString(req.Into), orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to merge labels")
+		return
+	}
+
+	utils.SendSuccess(c, "Labels merged", gin.H{"label": label, "moved_tasks": moved})
+}
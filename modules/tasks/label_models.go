@@ -0,0 +1,23 @@
+package tasks
+
+import "time"
+
+// DefaultLabelColor is used when a label is created without a color
+const DefaultLabelColor = "#6B7280"
+
+// Label tags tasks. Organization labels (no ProjectID) can be used on every task of
+// the org, project labels only on the tasks of their project. Names are unique
+// within a scope, ignoring case.
+type Label struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID uint      `gorm:"index" json:"organization_id"`
+	ProjectID      *uint     `gorm:"index" json:"project_id"` // nil for organization labels
+	Name           string    `json:"name"`
+	Color          string    `json:"color"` // "#RRGGBB"
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type TaskLabel struct {
+	TaskID  uint `gorm:"primaryKey"`
+	LabelID uint `gorm:"primaryKey;index"`
+}
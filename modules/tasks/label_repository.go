@@ -0,0 +1,159 @@
+package tasks
+
+import (
+	"gotask-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) CreateLabel(label *Label) error {
+	return r.db.Create(label).Error
+}
+
+func (r *repository) FindLabelByID(id string) (*Label, error) {
+	var label Label
+	err := r.db.Where("id = ?", id).First(&label).Error
+	return &label, err
+}
+
+// FindLabels lists the organization labels, plus the labels of one project when projectID is set
+func (r *repository) FindLabels(orgID string, projectID *uint) ([]Label, error) {
+	var labels []Label
+	query := r.db.Where("organization_id = ?", orgID)
+	if projectID != nil {
+		query = query.Where("(project_id IS NULL OR project_id = ?)", *projectID)
+	} else {
+		query = query.Where("project_id IS NULL")
+	}
+	err := query.Order("project_id NULLS FIRST, LOWER(name) asc, id asc").Find(&labels).Error
+	return labels, err
+}
+
+// LabelNameTaken reports whether another label of the same scope already has the name
+func (r *repository) LabelNameTaken(orgID uint, projectID *uint, name string, exceptID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&Label{}).
+		Where("organization_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", orgID, name, exceptID)
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	} else {
+		query = query.Where("project_id IS NULL")
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) UpdateLabel(label *Label, updates map[string]interface{}) error {
+	return r.db.Model(label).Updates(updates).Error
+}
+
+// DeleteLabel removes a label and takes it off every task
+func (r *repository) DeleteLabel(label *Label) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("label_id = ?", label.ID).Delete(&TaskLabel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(label).Error
+	})
+}
+
+// MergeLabels moves every task from source to target, then deletes source.
+// Tasks that already have both keep a single target row.
+func (r *repository) MergeLabels(source *Label, target *Label) (int64, error) {
+	var moved int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`INSERT INTO task_labels (task_id, label_id)
+			SELECT task_id, ? FROM task_labels WHERE label_id = ?
+			ON CONFLICT DO NOTHING`, target.ID, source.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+		if err := tx.Where("label_id = ?", source.ID).Delete(&TaskLabel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(source).Error
+	})
+	return moved, err
+}
+
+// FindUsableLabelIDs keeps the IDs of labels that tasks of the project may carry
+func (r *repository) FindUsableLabelIDs(orgID string, projectID uint, labelIDs []uint) ([]uint, error) {
+	var found []uint
+	if len(labelIDs) == 0 {
+		return found, nil
+	}
+	err := r.db.Model(&Label{}).
+		Where("id IN ? AND organization_id = ? AND (project_id IS NULL OR project_id = ?)", labelIDs, orgID, projectID).
+		Pluck("id", &found).Error
+	return found, err
+}
+
+// SetTaskLabels replaces the labels of a task
+func (r *repository) SetTaskLabels(taskID uint, labelIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return setTaskLabels(tx, taskID, labelIDs)
+	})
+}
+
+func setTaskLabels(tx *gorm.DB, taskID uint, labelIDs []uint) error {
+	if err := tx.Where("task_id = ?", taskID).Delete(&TaskLabel{}).Error; err != nil {
+		return err
+	}
+	if len(labelIDs) == 0 {
+		return nil
+	}
+	rows := make([]TaskLabel, 0, len(labelIDs))
+	for _, id := range labelIDs {
+		rows = append(rows, TaskLabel{TaskID: taskID, LabelID: id})
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// IsOrgAdmin reports whether the user administers the organization
+func (r *repository) IsOrgAdmin(orgID string, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("organization_users").
+		Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Helper: loads the labels of each task, ordered by name
+func (r *repository) fillLabels(tasks []Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	var rows []struct {
+		TaskID uint
+		Label  `gorm:"embedded"`
+	}
+	err := r.db.Table("task_labels").
+		Select("task_labels.task_id, labels.*").
+		Joins("JOIN labels ON labels.id = task_labels.label_id").
+		Where("task_labels.task_id IN ?", ids).
+		Order("LOWER(labels.name) asc, labels.id asc").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	labels := make(map[uint][]Label)
+	for _, row := range rows {
+		labels[row.TaskID] = append(labels[row.TaskID], row.Label)
+	}
+	for i := range tasks {
+		tasks[i].Labels = labels[tasks[i].ID]
+		if tasks[i].Labels == nil {
+			tasks[i].Labels = []Label{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"gotask-backend/models"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrLabelNotFound = errors.New("label not found")
+	ErrInvalidLabel  = errors.New("invalid label")
+	ErrLabelExists   = errors.New("a label with this name already exists")
+	ErrOrgAdminOnly  = errors.New("only organization admins can manage organization labels")
+)
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// LabelInput creates a label; ProjectID nil makes it an organization label
+type LabelInput struct {
+	ProjectID *uint
+	Name      string
+	Color     string
+}
+
+type UpdateLabelInput struct {
+	Name  *string
+	Color *string
+}
+
+// Helper: wraps ErrInvalidLabel with the reason
+func invalidLabel(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidLabel, fmt.Sprintf(format, args...))
+}
+
+// ValidateLabel checks a label name and color; an empty color means the default
+func ValidateLabel(name string, color string) error {
+	if strings.TrimSpace(name) == "" {
+		return invalidLabel("name is required")
+	}
+	if utf8.RuneCountInString(name) > 50 {
+		return invalidLabel("name must be at most 50 characters")
+	}
+	if color != "" && !labelColorPattern.MatchString(color) {
+		return invalidLabel("color must look like #1F2937")
+	}
+	return nil
+}
+
+// Helper: project labels are managed by project leads, organization labels by org admins
+func (s *taskService) authorizeLabelScope(projectID *uint, orgID string, userID uint) error {
+	if projectID != nil {
+		return s.AuthorizeProject(interfaceToString(*projectID), orgID, userID, models.ProjectRoleLead)
+	}
+	isAdmin, err := s.repo.IsOrgAdmin(orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return ErrOrgAdminOnly
+	}
+	return nil
+}
+
+// Helper: loads a label of the org and checks the caller may manage it.
+// Labels of projects the caller cannot see are reported as not found.
+func (s *taskService) authorizeLabel(id string, orgID string, userID uint) (*Label, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, ErrLabelNotFound
+	}
+	label, err := s.repo.FindLabelByID(id)
+	if err != nil || interfaceToString(label.OrganizationID) != orgID {
+		return nil, ErrLabelNotFound
+	}
+	err = s.authorizeLabelScope(label.ProjectID, orgID, userID)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil, ErrLabelNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+// Helper: checks a task's label IDs against the labels its project may use
+func (s *taskService) checkLabels(labelIDs []uint, projectID uint, orgID string) ([]uint, error) {
+	labelIDs = uniqueUints(labelIDs)
+	found, err := s.repo.FindUsableLabelIDs(orgID, projectID, labelIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) != len(labelIDs) {
+		return nil, invalidLabel("labels must belong to the organization or the task's project")
+	}
+	return labelIDs, nil
+}
+
+// GetLabels lists the organization labels, plus the project's own labels when projectID is given
+func (s *taskService) GetLabels(projectID string, orgID string, userID uint) ([]Label, error) {
+	var scope *uint
+	if projectID != "" {
+		if err := s.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer); err != nil {
+			return nil, err
+		}
+		id, err := strconv.ParseUint(projectID, 10, 64)
+		if err != nil {
+			return nil, ErrProjectNotFound
+		}
+		project := uint(id)
+		scope = &project
+	}
+
+	labels, err := s.repo.FindLabels(orgID, scope)
+	if err != nil {
+		return nil, err
+	}
+	if labels == nil {
+		labels = []Label{}
+	}
+	return labels, nil
+}
+
+func (s *taskService) CreateLabel(orgID string, userID uint, input LabelInput) (*Label, error) {
+	if err := s.authorizeLabelScope(input.ProjectID, orgID, userID); err != nil {
+		return nil, err
+	}
+	input.Name = strings.TrimSpace(input.Name)
+	if err := ValidateLabel(input.Name, input.Color); err != nil {
+		return nil, err
+	}
+	if input.Color == "" {
+		input.Color = DefaultLabelColor
+	}
+
+	org, err := strconv.ParseUint(orgID, 10, 64)
+	if err != nil {
+		return nil, ErrProjectNotFound
+	}
+	taken, err := s.repo.LabelNameTaken(uint(org), input.ProjectID, input.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrLabelExists
+	}
+
+	label := Label{
+		OrganizationID: uint(org),
+		ProjectID:      input.ProjectID,
+		Name:           input.Name,
+		Color:          strings.ToUpper(input.Color),
+	}
+	if err := s.repo.CreateLabel(&label); err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+func (s *taskService) UpdateLabel(id string, orgID string, userID uint, input UpdateLabelInput) (*Label, error) {
+	label, err := s.authorizeLabel(id, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, color := label.Name, label.Color
+	if input.Name != nil {
+		name = strings.TrimSpace(*input.Name)
+	}
+	if input.Color != nil {
+		color = strings.ToUpper(*input.Color)
+	}
+	if err := ValidateLabel(name, color); err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(name, label.Name) {
+		taken, err := s.repo.LabelNameTaken(label.OrganizationID, label.ProjectID, name, label.ID)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, ErrLabelExists
+		}
+	}
+
+	if err := s.repo.UpdateLabel(label, map[string]interface{}{"name": name, "color": color}); err != nil {
+		return nil, err
+	}
+	label.Name, label.Color = name, color
+	return label, nil
+}
+
+func (s *taskService) DeleteLabel(id string, orgID string, userID uint) error {
+	label, err := s.authorizeLabel(id, orgID, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteLabel(label)
+}
+
+// MergeLabels re-points every task from one label to another and deletes the first.
+// The target has to be usable wherever the source was: an organization label can
+// only merge into another organization label.
+func (s *taskService) MergeLabels(id string, targetID string, orgID string, userID uint) (*Label, int64, error) {
+	source, err := s.authorizeLabel(id, orgID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if targetID == id {
+		return nil, 0, invalidLabel("a label cannot be merged into itself")
+	}
+	// The target only has to be usable, not managed by the caller
+	if _, err := strconv.ParseUint(targetID, 10, 64); err != nil {
+		return nil, 0, invalidLabel("target label not found")
+	}
+	target, err := s.repo.FindLabelByID(targetID)
+	if err != nil || target.OrganizationID != source.OrganizationID {
+		return nil, 0, invalidLabel("target label not found")
+	}
+	if target.ProjectID != nil && (source.ProjectID == nil || *source.ProjectID != *target.ProjectID) {
+		return nil, 0, invalidLabel("labels can only be merged into organization labels or labels of the same project")
+	}
+
+	moved, err := s.repo.MergeLabels(source, target)
+	if err != nil {
+		return nil, 0, err
+	}
+	return target, moved, nil
+}
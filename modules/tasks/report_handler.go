@@ -0,0 +1,185 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	service ReportService
+}
+
+func NewReportHandler(service ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// GET /projects/:id/reports/cycle-time?priority_id=&assignee_id=&from=2024-01-01&to=2024-01-31
+func (h *ReportHandler) GetFlowTime(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var filter ReportFilter
+	if v := c.Query("priority_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "priority_id must be a number")
+			return
+		}
+		filter.PriorityID = uint(id)
+	}
+	if v := c.Query("assignee_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "assignee_id must be a number")
+			return
+		}
+		filter.AssigneeID = uint(id)
+	}
+	if !bindDateRange(c, &filter) {
+		return
+	}
+
+	report, err := h.service.GetFlowTime(projectID, orgID, user.ID, filter)
+	if err != nil {
+		sendTaskError(c, err, "Failed to build report")
+		return
+	}
+
+	utils.SendSuccess(c, "success", report)
+}
+
+// GET /projects/:id/reports/time?from=2024-01-01&to=2024-01-31
+func (h *ReportHandler) GetTimeTotals(c *gin.Context) {
+	projectID := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var filter ReportFilter
+	if !bindDateRange(c, &filter) {
+		return
+	}
+
+	totals, err := h.service.GetTimeTotals(projectID, orgID, user.ID, filter)
+	if err != nil {
+		sendTaskError(c, err, "Failed to build report")
+		return
+	}
+
+	utils.SendSuccess(c, "success", totals)
+}
+
+// GET /timesheet?user_id=4&week=2024-06-03&format=csv (week is any day of it, default this week)
+func (h *ReportHandler) GetTimesheet(c *gin.Context) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	targetUserID := user.ID
+	if v := c.Query("user_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "user_id must be a number")
+			return
+		}
+		targetUserID = uint(id)
+	}
+	week := time.Now()
+	if v := c.Query("week"); v != "" {
+		day, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "week must be a date like 2024-06-03")
+			return
+		}
+		week = day
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		utils.SendError(c, http.StatusBadRequest, "format must be 'json' or 'csv'")
+		return
+	}
+
+	timesheet, err := h.service.GetTimesheet(orgID, user.ID, targetUserID, week)
+	if err != nil {
+		sendTaskError(c, err, "Failed to build timesheet")
+		return
+	}
+
+	if format == "csv" {
+		filename := fmt.Sprintf("timesheet-%d-%s.csv", timesheet.UserID, timesheet.WeekStart.Format("2006-01-02"))
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", timesheetCSV(timesheet))
+		return
+	}
+	utils.SendSuccess(c, "success", timesheet)
+}
+
+// Helper: reads the optional from/to dates into the filter; false when a response was sent
+func bindDateRange(c *gin.Context, filter *ReportFilter) bool {
+	if v := c.Query("from"); v != "" {
+		from, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "from must be a date like 2024-01-01")
+			return false
+		}
+		filter.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "to must be a date like 2024-01-31")
+			return false
+		}
+		// "to" is inclusive for the caller, the query wants the next midnight
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	return true
+}
+
+// Helper: one row per task with the hours of each day, as billed, and a total row
+func timesheetCSV(timesheet *Timesheet) []byte {
+	hours := func(minutes int) string {
+		return strconv.FormatFloat(float64(minutes)/60, 'f', 2, 64)
+	}
+
+	header := []string{"Project", "Task", "Title"}
+	for day := 0; day < 7; day++ {
+		header = append(header, timesheet.WeekStart.AddDate(0, 0, day).Format("Mon 2006-01-02"))
+	}
+	header = append(header, "Total")
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(header)
+	for _, row := range timesheet.Rows {
+		record := []string{row.ProjectName, row.TaskKey, row.TaskTitle}
+		for _, minutes := range row.Minutes {
+			record = append(record, hours(minutes))
+		}
+		_ = w.Write(append(record, hours(row.TotalMinutes)))
+	}
+	total := []string{"Total", "", ""}
+	for _, minutes := range timesheet.DailyMinutes {
+		total = append(total, hours(minutes))
+	}
+	_ = w.Write(append(total, hours(timesheet.TotalMinutes)))
+	w.Flush()
+	return buf.Bytes()
+}
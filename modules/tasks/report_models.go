@@ -0,0 +1,85 @@
+package tasks
+
+import "time"
+
+// ReportFilter narrows the flow-time report; zero values mean "no filter"
+type ReportFilter struct {
+	PriorityID uint
+	AssigneeID uint
+	From       *time.Time // inclusive
+	To         *time.Time // exclusive
+}
+
+// DurationStats summarizes a set of durations in hours; the figures are null when Count is 0
+type DurationStats struct {
+	Count    int64    `json:"count"`
+	AvgHours *float64 `json:"avg_hours"`
+	P50Hours *float64 `json:"p50_hours"`
+	P75Hours *float64 `json:"p75_hours"`
+	P90Hours *float64 `json:"p90_hours"`
+	P95Hours *float64 `json:"p95_hours"`
+}
+
+// StatusDurationStats is how long tasks stay in one status
+type StatusDurationStats struct {
+	StatusID uint   `json:"status_id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	DurationStats
+}
+
+// FlowTimeReport is the response for GET /projects/:id/reports/cycle-time
+type FlowTimeReport struct {
+	ProjectID    uint                  `json:"project_id"`
+	LeadTime     DurationStats         `json:"lead_time"`  // created -> done
+	CycleTime    DurationStats         `json:"cycle_time"` // first in progress -> done
+	TimeInStatus []StatusDurationStats `json:"time_in_status"`
+}
+
+// UserTime is the time one user logged
+type UserTime struct {
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email"`
+	Minutes int    `json:"minutes"`
+}
+
+// TaskTime puts a task's estimates next to the time logged on it
+type TaskTime struct {
+	TaskID           uint   `json:"task_id"`
+	Key              string `json:"key"`
+	Title            string `json:"title"`
+	EstimateMinutes  *int   `json:"estimate_minutes"`
+	RemainingMinutes *int   `json:"remaining_minutes"`
+	SpentMinutes     int    `json:"spent_minutes"`
+}
+
+// TimeTotals is the response for GET /projects/:id/reports/time. Estimates cover
+// all tasks of the project, the date range only narrows the time spent.
+type TimeTotals struct {
+	ProjectID        uint       `json:"project_id"`
+	EstimateMinutes  int        `json:"estimate_minutes"`
+	RemainingMinutes int        `json:"remaining_minutes"`
+	SpentMinutes     int        `json:"spent_minutes"`
+	ByUser           []UserTime `json:"by_user"`
+	ByTask           []TaskTime `json:"by_task"` // tasks with an estimate or time logged
+}
+
+// TimesheetRow is the time a user logged on one task over a week, Monday first
+type TimesheetRow struct {
+	TaskID       uint   `json:"task_id"`
+	TaskKey      string `json:"task_key"`
+	TaskTitle    string `json:"task_title"`
+	ProjectID    uint   `json:"project_id"`
+	ProjectName  string `json:"project_name"`
+	Minutes      [7]int `json:"minutes"`
+	TotalMinutes int    `json:"total_minutes"`
+}
+
+// Timesheet is the response for GET /timesheet
+type Timesheet struct {
+	UserID       uint           `json:"user_id"`
+	WeekStart    time.Time      `json:"week_start"` // a Monday
+	Rows         []TimesheetRow `json:"rows"`
+	DailyMinutes [7]int         `json:"daily_minutes"`
+	TotalMinutes int            `json:"total_minutes"`
+}
@@ -0,0 +1,226 @@
+package tasks
+
+import (
+	"gotask-backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	LeadAndCycleTime(projectID uint, filter ReportFilter) (*DurationStats, *DurationStats, error)
+	TimeInStatus(projectID uint, filter ReportFilter) ([]StatusDurationStats, error)
+	TimeTotals(projectID uint, filter ReportFilter) (*TimeTotals, error)
+	TimesheetEntries(orgID string, userID uint, from time.Time, to time.Time) ([]timesheetEntry, error)
+	IsOrgAdmin(orgID string, userID uint) (bool, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db}
+}
+
+// Aggregates over a "hours" column, scanned into DurationStats
+const durationStatsColumns = `COUNT(*) AS count,
+	AVG(hours) AS avg_hours,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY hours) AS p50_hours,
+	percentile_cont(0.75) WITHIN GROUP (ORDER BY hours) AS p75_hours,
+	percentile_cont(0.9) WITHIN GROUP (ORDER BY hours) AS p90_hours,
+	percentile_cont(0.95) WITHIN GROUP (ORDER BY hours) AS p95_hours`
+
+// Helper: SQL conditions on "tasks" for the priority and assignee filters
+func taskFilterSQL(filter ReportFilter, args map[string]interface{}) string {
+	var conditions []string
+	if filter.PriorityID != 0 {
+		conditions = append(conditions, "tasks.priority_id = @priority")
+		args["priority"] = filter.PriorityID
+	}
+	if filter.AssigneeID != 0 {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM task_users WHERE task_users.task_id = tasks.id AND task_users.user_id = @assignee)")
+		args["assignee"] = filter.AssigneeID
+	}
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " AND " + strings.Join(conditions, " AND ")
+}
+
+// Helper: SQL conditions putting column inside the filter's date range
+func dateFilterSQL(column string, filter ReportFilter, args map[string]interface{}) string {
+	sql := ""
+	if filter.From != nil {
+		sql += " AND " + column + " >= @from"
+		args["from"] = *filter.From
+	}
+	if filter.To != nil {
+		sql += " AND " + column + " < @to"
+		args["to"] = *filter.To
+	}
+	return sql
+}
+
+// LeadAndCycleTime looks at tasks currently done. The completion time is the last move into a
+// "done" status, the start the first move into an "in progress" one. Tasks created straight
+// into "done" have no completing transition and are left out. The date range applies to completion.
+func (r *reportRepository) LeadAndCycleTime(projectID uint, filter ReportFilter) (*DurationStats, *DurationStats, error) {
+	args := map[string]interface{}{
+		"project":     projectID,
+		"done":        StatusCategoryDone,
+		"in_progress": StatusCategoryInProgress,
+	}
+	finished := `
+		WITH finished AS (
+			SELECT tasks.id, tasks.created_at,
+				MAX(tr.changed_at) FILTER (WHERE st.category = @done AND tr.from_status_id IS NOT NULL) AS done_at,
+				MIN(tr.changed_at) FILTER (WHERE st.category = @in_progress) AS started_at
+			FROM tasks
+			JOIN statuses current_status ON current_status.id = tasks.status_id
+			JOIN task_status_transitions tr ON tr.task_id = tasks.id
+			JOIN statuses st ON st.id = tr.to_status_id
+			WHERE tasks.project_id = @project AND tasks.deleted_at IS NULL
+				AND current_status.category = @done` + taskFilterSQL(filter, args) + `
+			GROUP BY tasks.id, tasks.created_at
+		)`
+	completed := dateFilterSQL("done_at", filter, args)
+
+	var lead DurationStats
+	if err := r.db.Raw(finished+`
+		SELECT `+durationStatsColumns+` FROM (
+			SELECT CAST(EXTRACT(EPOCH FROM done_at - created_at) / 3600 AS double precision) AS hours FROM finished
+			WHERE done_at IS NOT NULL`+completed+`
+		) durations`, args).Scan(&lead).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var cycle DurationStats
+	if err := r.db.Raw(finished+`
+		SELECT `+durationStatsColumns+` FROM (
+			SELECT CAST(EXTRACT(EPOCH FROM done_at - started_at) / 3600 AS double precision) AS hours FROM finished
+			WHERE done_at IS NOT NULL AND started_at IS NOT NULL AND started_at <= done_at`+completed+`
+		) durations`, args).Scan(&cycle).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return &lead, &cycle, nil
+}
+
+// TimeInStatus measures every stay of a task in a status, from the transition into it until
+// the next one (or now, for the current status). The date range applies to when the stay began.
+func (r *reportRepository) TimeInStatus(projectID uint, filter ReportFilter) ([]StatusDurationStats, error) {
+	args := map[string]interface{}{"project": projectID}
+	var stats []StatusDurationStats
+	err := r.db.Raw(`
+		WITH stays AS (
+			SELECT tr.to_status_id AS status_id, tr.changed_at,
+				CAST(EXTRACT(EPOCH FROM LEAD(tr.changed_at, 1, NOW()) OVER (PARTITION BY tr.task_id ORDER BY tr.changed_at, tr.id) - tr.changed_at) / 3600 AS double precision) AS hours
+			FROM task_status_transitions tr
+			JOIN tasks ON tasks.id = tr.task_id
+			WHERE tasks.project_id = @project AND tasks.deleted_at IS NULL`+taskFilterSQL(filter, args)+`
+		)
+		SELECT statuses.id AS status_id, statuses.name, statuses.category, `+durationStatsColumns+`
+		FROM stays
+		JOIN statuses ON statuses.id = stays.status_id
+		WHERE TRUE`+dateFilterSQL("stays.changed_at", filter, args)+`
+		GROUP BY statuses.id, statuses.name, statuses.category, statuses.index
+		ORDER BY statuses.index asc`, args).Scan(&stats).Error
+	return stats, err
+}
+
+// TimeTotals sums the estimates of the project's tasks and the time logged on them,
+// per user and per task. The date range applies to the day the time was logged.
+func (r *reportRepository) TimeTotals(projectID uint, filter ReportFilter) (*TimeTotals, error) {
+	totals := TimeTotals{ProjectID: projectID}
+	err := r.db.Raw(`
+		SELECT COALESCE(SUM(estimate_minutes), 0) AS estimate_minutes, COALESCE(SUM(remaining_minutes), 0) AS remaining_minutes
+		FROM tasks WHERE project_id = ? AND deleted_at IS NULL`, projectID).Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	args := map[string]interface{}{"project": projectID}
+	logged := " AND " + loggedTimeEntries + dateFilterSQL("time_entries.date", filter, args)
+
+	if err := r.db.Raw(`
+		SELECT time_entries.user_id, users.email, SUM(time_entries.minutes) AS minutes
+		FROM time_entries
+		JOIN tasks ON tasks.id = time_entries.task_id
+		JOIN users ON users.id = time_entries.user_id
+		WHERE tasks.project_id = @project AND tasks.deleted_at IS NULL`+logged+`
+		GROUP BY time_entries.user_id, users.email
+		ORDER BY minutes desc, users.email asc`, args).Scan(&totals.ByUser).Error; err != nil {
+		return nil, err
+	}
+
+	var tasks []struct {
+		TaskTime
+		Number     uint
+		ProjectKey string
+	}
+	if err := r.db.Raw(`
+		SELECT tasks.id AS task_id, tasks.number, projects.key AS project_key, tasks.title,
+			tasks.estimate_minutes, tasks.remaining_minutes, COALESCE(SUM(time_entries.minutes), 0) AS spent_minutes
+		FROM tasks
+		JOIN projects ON projects.id = tasks.project_id
+		LEFT JOIN time_entries ON time_entries.task_id = tasks.id`+logged+`
+		WHERE tasks.project_id = @project AND tasks.deleted_at IS NULL
+		GROUP BY tasks.id, projects.key
+		HAVING tasks.estimate_minutes IS NOT NULL OR COUNT(time_entries.id) > 0
+		ORDER BY tasks.number asc, tasks.id asc`, args).Scan(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	totals.ByTask = make([]TaskTime, 0, len(tasks))
+	for _, t := range tasks {
+		t.Key = formatTaskKey(t.ProjectKey, t.Number)
+		totals.ByTask = append(totals.ByTask, t.TaskTime)
+	}
+	if totals.ByUser == nil {
+		totals.ByUser = []UserTime{}
+	}
+	for _, u := range totals.ByUser {
+		totals.SpentMinutes += u.Minutes
+	}
+	return &totals, nil
+}
+
+// One task and day of a timesheet
+type timesheetEntry struct {
+	TaskID      uint
+	Number      uint
+	ProjectKey  string
+	Title       string
+	ProjectID   uint
+	ProjectName string
+	Date        time.Time
+	Minutes     int
+}
+
+// TimesheetEntries sums the time a user logged in the org per task and day, in [from, to)
+func (r *reportRepository) TimesheetEntries(orgID string, userID uint, from time.Time, to time.Time) ([]timesheetEntry, error) {
+	var entries []timesheetEntry
+	err := r.db.Raw(`
+		SELECT tasks.id AS task_id, tasks.number, projects.key AS project_key, tasks.title,
+			projects.id AS project_id, projects.name AS project_name,
+			time_entries.date, SUM(time_entries.minutes) AS minutes
+		FROM time_entries
+		JOIN tasks ON tasks.id = time_entries.task_id AND tasks.deleted_at IS NULL
+		JOIN projects ON projects.id = tasks.project_id AND projects.deleted_at IS NULL
+		WHERE projects.organization_id = ? AND time_entries.user_id = ?
+			AND time_entries.date >= ? AND time_entries.date < ? AND `+loggedTimeEntries+`
+		GROUP BY tasks.id, projects.id, time_entries.date
+		ORDER BY projects.name asc, tasks.number asc, time_entries.date asc`,
+		orgID, userID, from, to).Scan(&entries).Error
+	return entries, err
+}
+
+func (r *reportRepository) IsOrgAdmin(orgID string, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("organization_users").
+		Where("user_id = ? AND organization_id = ? AND role = ?", userID, orgID, models.OrgRoleAdmin).
+		Count(&count).Error
+	return count > 0, err
+}
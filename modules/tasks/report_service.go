@@ -0,0 +1,111 @@
+package tasks
+
+import (
+	"errors"
+	"gotask-backend/models"
+	"strconv"
+	"time"
+)
+
+type ReportService interface {
+	GetFlowTime(projectID string, orgID string, userID uint, filter ReportFilter) (*FlowTimeReport, error)
+	GetTimeTotals(projectID string, orgID string, userID uint, filter ReportFilter) (*TimeTotals, error)
+	GetTimesheet(orgID string, userID uint, targetUserID uint, week time.Time) (*Timesheet, error)
+}
+
+var ErrTimesheetForbidden = errors.New("only organization admins can view other users' timesheets")
+
+type reportService struct {
+	repo        ReportRepository
+	taskService TaskService
+}
+
+func NewReportService(repo ReportRepository, taskService TaskService) ReportService {
+	return &reportService{repo, taskService}
+}
+
+func (s *reportService) GetFlowTime(projectID string, orgID string, userID uint, filter ReportFilter) (*FlowTimeReport, error) {
+	if err := s.taskService.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(projectID, 10, 64)
+	if err != nil {
+		return nil, ErrProjectNotFound
+	}
+
+	lead, cycle, err := s.repo.LeadAndCycleTime(uint(id), filter)
+	if err != nil {
+		return nil, err
+	}
+	timeInStatus, err := s.repo.TimeInStatus(uint(id), filter)
+	if err != nil {
+		return nil, err
+	}
+	if timeInStatus == nil {
+		timeInStatus = []StatusDurationStats{}
+	}
+
+	return &FlowTimeReport{
+		ProjectID:    uint(id),
+		LeadTime:     *lead,
+		CycleTime:    *cycle,
+		TimeInStatus: timeInStatus,
+	}, nil
+}
+
+func (s *reportService) GetTimeTotals(projectID string, orgID string, userID uint, filter ReportFilter) (*TimeTotals, error) {
+	if err := s.taskService.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(projectID, 10, 64)
+	if err != nil {
+		return nil, ErrProjectNotFound
+	}
+	return s.repo.TimeTotals(uint(id), filter)
+}
+
+// GetTimesheet lays out the time a user logged in the org during the week that
+// contains the given day. Users see their own; org admins see everyone's.
+func (s *reportService) GetTimesheet(orgID string, userID uint, targetUserID uint, week time.Time) (*Timesheet, error) {
+	if targetUserID != userID {
+		admin, err := s.repo.IsOrgAdmin(orgID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !admin {
+			return nil, ErrTimesheetForbidden
+		}
+	}
+
+	// Weeks start on Monday
+	start := entryDate(week)
+	start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+
+	entries, err := s.repo.TimesheetEntries(orgID, targetUserID, start, start.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet := Timesheet{UserID: targetUserID, WeekStart: start, Rows: []TimesheetRow{}}
+	rows := make(map[uint]int) // task ID -> index in Rows
+	for _, e := range entries {
+		i, ok := rows[e.TaskID]
+		if !ok {
+			i = len(timesheet.Rows)
+			rows[e.TaskID] = i
+			timesheet.Rows = append(timesheet.Rows, TimesheetRow{
+				TaskID:      e.TaskID,
+				TaskKey:     formatTaskKey(e.ProjectKey, e.Number),
+				TaskTitle:   e.Title,
+				ProjectID:   e.ProjectID,
+				ProjectName: e.ProjectName,
+			})
+		}
+		day := int(entryDate(e.Date).Sub(start).Hours() / 24)
+		timesheet.Rows[i].Minutes[day] += e.Minutes
+		timesheet.Rows[i].TotalMinutes += e.Minutes
+		timesheet.DailyMinutes[day] += e.Minutes
+		timesheet.TotalMinutes += e.Minutes
+	}
+	return &timesheet, nil
+}
@@ -0,0 +1,10 @@
+package tasks
+
+// MaxTaskDepth is how many levels a hierarchy may have: a task, its subtasks and theirs
+const MaxTaskDepth = 3
+
+// SubtaskProgress rolls up the subtasks below a task, at any depth
+type SubtaskProgress struct {
+	Total int64 `json:"total"`
+	Done  int64 `json:"done"` // subtasks in a "done" status
+}
@@ -0,0 +1,140 @@
+package tasks
+
+import "time"
+
+// Walks down from the seed tasks to every live subtask; root_id is the seed a row descends from
+const subtreeCTE = `
+	WITH RECURSIVE subtree AS (
+		SELECT id AS root_id, id FROM tasks WHERE id IN ?
+		UNION ALL
+		SELECT subtree.root_id, tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+		WHERE tasks.deleted_at IS NULL
+	)`
+
+// FindAncestorIDs returns the chain of parents above a task, nearest first
+func (r *repository) FindAncestorIDs(taskID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id AS id, 1 AS depth FROM tasks WHERE id = ?
+			UNION ALL
+			SELECT tasks.parent_id, ancestors.depth + 1 FROM tasks JOIN ancestors ON tasks.id = ancestors.id
+		)
+		SELECT id FROM ancestors WHERE id IS NOT NULL ORDER BY depth`, taskID).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// SubtreeHeight counts the levels of a task and its subtasks; a task without subtasks is 1
+func (r *repository) SubtreeHeight(taskID uint) (int, error) {
+	var height int
+	err := r.db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id, 1 AS depth FROM tasks WHERE id = ?
+			UNION ALL
+			SELECT tasks.id, subtree.depth + 1 FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+			WHERE tasks.deleted_at IS NULL
+		)
+		SELECT MAX(depth) FROM subtree`, taskID).
+		Scan(&height).Error
+	return height, err
+}
+
+// FindSubtasks returns the direct subtasks of a task
+func (r *repository) FindSubtasks(taskID uint) ([]Task, error) {
+	var subtasks []Task
+	err := r.db.Preload("Status").
+		Preload("Priority").
+		Where("tasks.parent_id = ?", taskID).
+		Order("tasks.number asc").
+		Find(&subtasks).Error
+	if err != nil {
+		return nil, err
+	}
+	r.fillDetails(subtasks)
+	return subtasks, nil
+}
+
+// FindDescendants returns every live subtask below the given tasks, at any depth
+func (r *repository) FindDescendants(taskIDs []uint) ([]Task, error) {
+	var descendants []Task
+	if len(taskIDs) == 0 {
+		return descendants, nil
+	}
+
+	var ids []uint
+	if err := r.db.Raw(subtreeCTE+" SELECT id FROM subtree WHERE id <> root_id", taskIDs).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return descendants, nil
+	}
+
+	err := r.db.Preload("Status").
+		Preload("Priority").
+		Where("tasks.id IN ?", ids).
+		Order("tasks.number asc").
+		Find(&descendants).Error
+	if err != nil {
+		return nil, err
+	}
+	r.fillDetails(descendants)
+	return descendants, nil
+}
+
+// Helper: rolls subtask progress up onto each task that has subtasks
+func (r *repository) fillProgress(tasks []Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	var rows []struct {
+		RootID uint
+		Total  int64
+		Done   int64
+	}
+	err := r.db.Raw(subtreeCTE+`
+		SELECT subtree.root_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE statuses.category = ?) AS done
+		FROM subtree
+		JOIN tasks ON tasks.id = subtree.id
+		JOIN statuses ON statuses.id = tasks.status_id
+		WHERE subtree.id <> subtree.root_id
+		GROUP BY subtree.root_id`, ids, StatusCategoryDone).
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	progress := make(map[uint]*SubtaskProgress)
+	for _, row := range rows {
+		progress[row.RootID] = &SubtaskProgress{Total: row.Total, Done: row.Done}
+	}
+	for i := range tasks {
+		tasks[i].Progress = progress[tasks[i].ID]
+	}
+	return nil
+}
+
+// Delete moves the task and its live subtasks, at any depth, to the trash with the same
+// timestamp, so a restore brings back exactly the subtasks that went with it
+func (r *repository) Delete(task *Task) error {
+	return r.db.Exec(subtreeCTE+" UPDATE tasks SET deleted_at = ? WHERE id IN (SELECT id FROM subtree)",
+		[]uint{task.ID}, time.Now()).Error
+}
+
+func (r *repository) Restore(task *Task) error {
+	return r.db.Exec(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = ?
+			UNION ALL
+			SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+			WHERE tasks.deleted_at = ?
+		)
+		UPDATE tasks SET deleted_at = NULL WHERE id IN (SELECT id FROM subtree)`,
+		task.ID, task.DeletedAt.Time).Error
+}
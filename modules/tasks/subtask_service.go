@@ -0,0 +1,84 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"gotask-backend/models"
+)
+
+var (
+	ErrInvalidParent = errors.New("parent must be a task of the same project")
+	ErrTaskCycle     = errors.New("a task cannot be moved under itself or one of its subtasks")
+	ErrTaskTooDeep   = fmt.Errorf("subtasks can only be nested %d levels deep", MaxTaskDepth)
+	ErrParentTrashed = errors.New("the parent task is in the trash, restore it first")
+)
+
+// Helper: a task can only hang under a live task of its own project, never under
+// itself or one of its own subtasks, and the hierarchy stays within MaxTaskDepth.
+// taskID is 0 for a task that is being created.
+func (s *taskService) checkParent(taskID uint, parentID uint, projectID uint) error {
+	if parentID == taskID {
+		return ErrTaskCycle
+	}
+	parent, err := s.repo.FindByID(interfaceToString(parentID))
+	if err != nil || parent.ProjectID != projectID {
+		return ErrInvalidParent
+	}
+
+	ancestors, err := s.repo.FindAncestorIDs(parentID)
+	if err != nil {
+		return err
+	}
+	if containsUint(ancestors, taskID) {
+		return ErrTaskCycle
+	}
+
+	height := 1
+	if taskID != 0 {
+		if height, err = s.repo.SubtreeHeight(taskID); err != nil {
+			return err
+		}
+	}
+	// The parent sits on level len(ancestors)+1, the task's subtree starts below it
+	if len(ancestors)+1+height > MaxTaskDepth {
+		return ErrTaskTooDeep
+	}
+	return nil
+}
+
+// GetSubtasks returns the direct subtasks of a task
+func (s *taskService) GetSubtasks(ref string, orgID string, userID uint) ([]Task, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindSubtasks(task.ID)
+}
+
+// Helper: nests every subtask below its parent, for the tree mode of the task list
+func (s *taskService) buildTaskTree(roots []Task) ([]Task, error) {
+	ids := make([]uint, 0, len(roots))
+	for _, t := range roots {
+		ids = append(ids, t.ID)
+	}
+	descendants, err := s.repo.FindDescendants(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]Task)
+	for _, d := range descendants {
+		children[*d.ParentID] = append(children[*d.ParentID], d)
+	}
+	var attach func(task *Task)
+	attach = func(task *Task) {
+		task.Subtasks = children[task.ID]
+		for i := range task.Subtasks {
+			attach(&task.Subtasks[i])
+		}
+	}
+	for i := range roots {
+		attach(&roots[i])
+	}
+	return roots, nil
+}
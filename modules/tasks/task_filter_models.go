@@ -0,0 +1,77 @@
+package tasks
+
+import "time"
+
+// Sort keys of the task list; "cf.<id>" sorts on a custom field
+const (
+	TaskSortCreated   = "created"
+	TaskSortCompleted = "completed"
+	TaskSortTitle     = "title"
+	TaskSortNumber    = "number"
+	TaskSortStatus    = "status"   // board column order
+	TaskSortPriority  = "priority" // priority level
+	TaskSortStartDate = "start_date"
+	TaskSortEndDate   = "end_date"
+)
+
+// TaskListQuery is the raw query of GET /projects/:id/tasks
+type TaskListQuery struct {
+	Page  int
+	Limit int
+
+	Status   string // comma separated status IDs
+	Priority string // comma separated priority IDs
+	Assignee string // comma separated user IDs, "me" and "unassigned"
+	Search   string // matched against the title, or the number for "42" and "WEB-42"
+
+	Label      string // comma separated label IDs
+	LabelMatch string // "any" (default) or "all" of the labels
+
+	StartFrom   string // dates like 2024-01-31, every range is inclusive
+	StartTo     string
+	EndFrom     string
+	EndTo       string
+	CreatedFrom string
+	CreatedTo   string
+
+	CustomFieldFilters map[string]string // "cf.12=value" arrives as {"12": "value"}, "cf.12.min=5" as {"12.min": "5"}
+	Sort               string            // comma separated sort keys, a "-" prefix sorts that key descending
+	Order              string            // "asc" or "desc", the direction of keys without a prefix
+	Tree               bool              // filters, sorting and paging apply to top-level tasks, which bring their subtasks
+}
+
+// DateRange bounds a date column; To is exclusive
+type DateRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// TaskSort is one key of the task list order, already checked against the allowed keys
+type TaskSort struct {
+	Key         string       // one of the TaskSort constants, empty for custom fields
+	CustomField *CustomField // set for "cf.<id>" keys
+	Desc        bool
+}
+
+// TaskListOptions drives paging, filtering and sorting of GET /projects/:id/tasks
+type TaskListOptions struct {
+	Page  int
+	Limit int
+
+	StatusIDs   []uint
+	PriorityIDs []uint
+	AssigneeIDs []uint
+	Unassigned  bool // with AssigneeIDs, tasks matching either are listed
+	LabelIDs    []uint
+	AllLabels   bool // tasks need every label in LabelIDs, not just one
+	RootsOnly   bool // only top-level tasks
+	Search      string
+	Number      uint // set when the search looks like a task number
+
+	StartDate DateRange
+	EndDate   DateRange
+	Created   DateRange
+
+	CustomFieldFilters []CustomFieldFilter
+	Sort               []TaskSort // empty keeps the default order (newest first)
+}
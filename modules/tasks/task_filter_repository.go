@@ -0,0 +1,82 @@
+package tasks
+
+import "gorm.io/gorm"
+
+// SQL expressions behind the sort keys; only keys from this map reach the query
+var taskSortColumns = map[string]string{
+	TaskSortCreated:   "tasks.created_at",
+	TaskSortCompleted: "tasks.completed_at",
+	TaskSortTitle:     "LOWER(tasks.title)",
+	TaskSortNumber:    "tasks.number",
+	TaskSortStatus:    "(SELECT statuses.index FROM statuses WHERE statuses.id = tasks.status_id)",
+	TaskSortPriority:  "(SELECT priorities.level FROM priorities WHERE priorities.id = tasks.priority_id)",
+	TaskSortStartDate: "tasks.start_date",
+	TaskSortEndDate:   "tasks.end_date",
+}
+
+// Helper: adds the WHERE conditions of the task list
+func applyTaskFilters(query *gorm.DB, options TaskListOptions) *gorm.DB {
+	if options.RootsOnly {
+		query = query.Where("tasks.parent_id IS NULL")
+	}
+	if len(options.StatusIDs) > 0 {
+		query = query.Where("tasks.status_id IN ?", options.StatusIDs)
+	}
+	if len(options.PriorityIDs) > 0 {
+		query = query.Where("tasks.priority_id IN ?", options.PriorityIDs)
+	}
+
+	assigned := "EXISTS (SELECT 1 FROM task_users WHERE task_users.task_id = tasks.id AND task_users.user_id IN ?)"
+	unassigned := "NOT EXISTS (SELECT 1 FROM task_users WHERE task_users.task_id = tasks.id)"
+	switch {
+	case len(options.AssigneeIDs) > 0 && options.Unassigned:
+		query = query.Where("("+assigned+" OR "+unassigned+")", options.AssigneeIDs)
+	case len(options.AssigneeIDs) > 0:
+		query = query.Where(assigned, options.AssigneeIDs)
+	case options.Unassigned:
+		query = query.Where(unassigned)
+	}
+
+	switch {
+	case len(options.LabelIDs) > 0 && options.AllLabels:
+		query = query.Where(`tasks.id IN (SELECT task_id FROM task_labels WHERE label_id IN ?
+			GROUP BY task_id HAVING COUNT(*) = ?)`, options.LabelIDs, len(options.LabelIDs))
+	case len(options.LabelIDs) > 0:
+		query = query.Where("EXISTS (SELECT 1 FROM task_labels WHERE task_labels.task_id = tasks.id AND task_labels.label_id IN ?)", options.LabelIDs)
+	}
+
+	if options.Search != "" {
+		if options.Number > 0 {
+			query = query.Where("(tasks.title ILIKE ? OR tasks.number = ?)", options.Search, options.Number)
+		} else {
+			query = query.Where("tasks.title ILIKE ?", options.Search)
+		}
+	}
+
+	query = applyDateRange(query, "tasks.start_date", options.StartDate)
+	query = applyDateRange(query, "tasks.end_date", options.EndDate)
+	query = applyDateRange(query, "tasks.created_at", options.Created)
+
+	return applyCustomFieldFilters(query, options.CustomFieldFilters)
+}
+
+func applyDateRange(query *gorm.DB, column string, r DateRange) *gorm.DB {
+	if r.From != nil {
+		query = query.Where(column+" >= ?", *r.From)
+	}
+	if r.To != nil {
+		query = query.Where(column+" < ?", *r.To)
+	}
+	return query
+}
+
+// Helper: SQL ordering of one sort key, tasks without a value go last
+func taskOrder(sort TaskSort) string {
+	if sort.CustomField != nil {
+		return customFieldOrder(sort.CustomField, sort.Desc)
+	}
+	if sort.Desc {
+		return taskSortColumns[sort.Key] + " DESC NULLS LAST"
+	}
+	return taskSortColumns[sort.Key] + " ASC NULLS LAST"
+}
@@ -0,0 +1,192 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTaskFilter = errors.New("invalid task filter")
+
+// Helper: wraps a validation message so handlers can map it to a 400
+func invalidTaskFilter(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidTaskFilter, fmt.Sprintf(format, args...))
+}
+
+// IsValidTaskSort checks a sort key (without its "-" prefix) coming from user input
+func IsValidTaskSort(key string) bool {
+	switch key {
+	case TaskSortCreated, TaskSortCompleted, TaskSortTitle, TaskSortNumber,
+		TaskSortStatus, TaskSortPriority, TaskSortStartDate, TaskSortEndDate:
+		return true
+	}
+	return strings.HasPrefix(key, "cf.")
+}
+
+// Helper: turns the raw list query into checked options for the repository
+func (s *taskService) buildTaskListOptions(projectID uint, userID uint, query TaskListQuery) (*TaskListOptions, error) {
+	options := TaskListOptions{Page: query.Page, Limit: query.Limit, RootsOnly: query.Tree}
+	var err error
+
+	if options.StatusIDs, err = parseIDList(query.Status, "status"); err != nil {
+		return nil, err
+	}
+	if options.PriorityIDs, err = parseIDList(query.Priority, "priority"); err != nil {
+		return nil, err
+	}
+	for _, part := range splitList(query.Assignee) {
+		switch part {
+		case "me":
+			options.AssigneeIDs = append(options.AssigneeIDs, userID)
+		case "unassigned":
+			options.Unassigned = true
+		default:
+			id, err := strconv.ParseUint(part, 10, 64)
+			if err != nil {
+				return nil, invalidTaskFilter("assignee must be user IDs, 'me' or 'unassigned'")
+			}
+			options.AssigneeIDs = append(options.AssigneeIDs, uint(id))
+		}
+	}
+	options.AssigneeIDs = uniqueUints(options.AssigneeIDs)
+
+	if options.LabelIDs, err = parseIDList(query.Label, "label"); err != nil {
+		return nil, err
+	}
+	options.LabelIDs = uniqueUints(options.LabelIDs)
+	switch query.LabelMatch {
+	case "", "any":
+	case "all":
+		options.AllLabels = true
+	default:
+		return nil, invalidTaskFilter("label_match must be 'any' or 'all'")
+	}
+
+	if search := strings.TrimSpace(query.Search); search != "" {
+		options.Search = "%" + escapeLike(search) + "%"
+		number := search
+		if m := taskKeyPattern.FindStringSubmatch(search); m != nil {
+			number = m[2]
+		}
+		if n, err := strconv.ParseUint(number, 10, 32); err == nil {
+			options.Number = uint(n)
+		}
+	}
+
+	if options.StartDate, err = parseDateRange(query.StartFrom, query.StartTo, "start"); err != nil {
+		return nil, err
+	}
+	if options.EndDate, err = parseDateRange(query.EndFrom, query.EndTo, "end"); err != nil {
+		return nil, err
+	}
+	if options.Created, err = parseDateRange(query.CreatedFrom, query.CreatedTo, "created"); err != nil {
+		return nil, err
+	}
+
+	sortKeys := splitList(query.Sort)
+	usesCustomFields := len(query.CustomFieldFilters) > 0
+	for _, key := range sortKeys {
+		if strings.HasPrefix(strings.TrimPrefix(key, "-"), "cf.") {
+			usesCustomFields = true
+		}
+	}
+
+	// Custom field filters and sorting are resolved against the project's fields
+	var fields []CustomField
+	if usesCustomFields {
+		if fields, err = s.repo.FindCustomFields(projectID); err != nil {
+			return nil, err
+		}
+	}
+	for key, value := range query.CustomFieldFilters {
+		filter, err := ParseCustomFieldFilter(fields, key, value)
+		if err != nil {
+			return nil, err
+		}
+		options.CustomFieldFilters = append(options.CustomFieldFilters, *filter)
+	}
+
+	seen := make(map[string]bool)
+	for _, key := range sortKeys {
+		sort := TaskSort{Desc: query.Order == "desc"}
+		if trimmed, ok := strings.CutPrefix(key, "-"); ok {
+			key, sort.Desc = trimmed, true
+		}
+		if !IsValidTaskSort(key) {
+			return nil, invalidTaskFilter("unknown sort key '%s'", key)
+		}
+		if seen[key] {
+			return nil, invalidTaskFilter("sort key '%s' is repeated", key)
+		}
+		seen[key] = true
+
+		if fieldID, ok := strings.CutPrefix(key, "cf."); ok {
+			for i := range fields {
+				if interfaceToString(fields[i].ID) == fieldID {
+					sort.CustomField = &fields[i]
+				}
+			}
+			if sort.CustomField == nil {
+				return nil, invalidCustomField("unknown custom field '%s'", fieldID)
+			}
+			if sort.CustomField.Type == CustomFieldMultiSelect {
+				return nil, invalidCustomField("multi-select fields cannot be sorted")
+			}
+		} else {
+			sort.Key = key
+		}
+		options.Sort = append(options.Sort, sort)
+	}
+
+	return &options, nil
+}
+
+// Helper: splits a comma separated query value, ignoring empty entries
+func splitList(raw string) []string {
+	var parts []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func parseIDList(raw string, name string) ([]uint, error) {
+	var ids []uint
+	for _, part := range splitList(raw) {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, invalidTaskFilter("%s must be a comma separated list of IDs", name)
+		}
+		ids = append(ids, uint(id))
+	}
+	return uniqueUints(ids), nil
+}
+
+// Helper: parses "<name>_from" and "<name>_to"; "to" is inclusive for the caller,
+// the query wants the next midnight
+func parseDateRange(from string, to string, name string) (DateRange, error) {
+	var r DateRange
+	if from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return r, invalidTaskFilter("%s_from must be a date like 2024-01-01", name)
+		}
+		r.From = &t
+	}
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return r, invalidTaskFilter("%s_to must be a date like 2024-01-31", name)
+		}
+		t = t.AddDate(0, 0, 1)
+		r.To = &t
+	}
+	if r.From != nil && r.To != nil && !r.From.Before(*r.To) {
+		return r, invalidTaskFilter("%s_from must not be after %s_to", name, name)
+	}
+	return r, nil
+}
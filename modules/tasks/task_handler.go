@@ -1,10 +1,13 @@
 package tasks
 
 import (
+	"errors"
+	"gotask-backend/modules/auth"
 	"gotask-backend/utils"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,24 +21,68 @@ func NewTaskHandler(service TaskService) *Handler {
 	return &Handler{service: service}
 }
 
-// GET /projects/:id/tasks
+// GET /projects/:id/tasks?status_id=1,2&priority_id=&assignee=me,unassigned&label_id=3,4&label_match=all&q=&start_from=&end_to=&created_from=&sort=priority,-created&tree=true
 func (h *Handler) FindTasksByProject(c *gin.Context) {
 	projectID := c.Param("id")
 
-	orgIDInterface, exists := c.Get("org_id")
-	if !exists {
-		utils.SendError(c, http.StatusBadRequest, "X-Organization-ID header is required")
+	orgID, ok := requireOrgID(c)
+	if !ok {
 		return
 	}
-	orgID := orgIDInterface.(string)
+	user := c.MustGet("user").(auth.User)
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 200 {
+		utils.SendError(c, http.StatusBadRequest, "limit must be between 1 and 200")
+		return
+	}
 
-	tasks, total, err := h.service.GetTasksByProject(projectID, orgID, page, limit)
+	query := TaskListQuery{
+		Page:     page,
+		Limit:    limit,
+		Status:   c.Query("status_id"),
+		Priority: c.Query("priority_id"),
+		Assignee: c.Query("assignee"),
+		Search:   c.Query("q"),
+
+		Label:      c.Query("label_id"),
+		LabelMatch: c.Query("label_match"),
+
+		StartFrom:   c.Query("start_from"),
+		StartTo:     c.Query("start_to"),
+		EndFrom:     c.Query("end_from"),
+		EndTo:       c.Query("end_to"),
+		CreatedFrom: c.Query("created_from"),
+		CreatedTo:   c.Query("created_to"),
+
+		CustomFieldFilters: make(map[string]string),
+		Sort:               c.Query("sort"),
+		Order:              c.DefaultQuery("order", "asc"),
+	}
+	tree, err := strconv.ParseBool(c.DefaultQuery("tree", "false"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "tree must be 'true' or 'false'")
+		return
+	}
+	query.Tree = tree
+	if query.Order != "asc" && query.Order != "desc" {
+		utils.SendError(c, http.StatusBadRequest, "order must be 'asc' or 'desc'")
+		return
+	}
+	for key, values := range c.Request.URL.Query() {
+		if field, ok := strings.CutPrefix(key, "cf."); ok && len(values) > 0 {
+			query.CustomFieldFilters[field] = values[0]
+		}
+	}
+
+	tasks, total, err := h.service.GetTasksByProject(projectID, orgID, user.ID, query)
 
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch tasks")
+		sendTaskError(c, err, "Failed to fetch tasks")
 		return
 	}
 
@@ -52,15 +99,47 @@ func (h *Handler) FindTasksByProject(c *gin.Context) {
 	})
 }
 
+// GET /tasks/:id (numeric ID or key like WEB-42)
+func (h *Handler) GetTask(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	task, err := h.service.GetTask(ref, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch task")
+		return
+	}
+
+	utils.SendSuccess(c, "success", task)
+}
+
 // POST /tasks
 func (h *Handler) CreateTask(c *gin.Context) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
 	var req struct {
-		Title      string     `json:"title" binding:"required"`
-		ProjectID  uint       `json:"project_id" binding:"required"`
-		StatusID   uint       `json:"status_id"`
-		PriorityID uint       `json:"priority_id"`
-		StartDate  *time.Time `json:"start_date"`
-		EndDate    *time.Time `json:"end_date"`
+		Title       string     `json:"title" binding:"required"`
+		Description string     `json:"description"`
+		ProjectID   uint       `json:"project_id" binding:"required"`
+		StatusID    uint       `json:"status_id"`
+		PriorityID  uint       `json:"priority_id"`
+		MilestoneID *uint      `json:"milestone_id"`
+		SprintID    *uint      `json:"sprint_id"`
+		ParentID    *uint      `json:"parent_id"`
+		LabelIDs    []uint     `json:"label_ids"`
+		StartDate   *time.Time `json:"start_date"`
+		EndDate     *time.Time `json:"end_date"`
+
+		EstimateMinutes *int                   `json:"estimate_minutes"`
+		CustomFields    map[string]interface{} `json:"custom_fields"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -69,17 +148,25 @@ func (h *Handler) CreateTask(c *gin.Context) {
 	}
 
 	input := CreateTaskInput{
-		Title:      req.Title,
-		ProjectID:  req.ProjectID,
-		StatusID:   req.StatusID,
-		PriorityID: req.PriorityID,
-		StartDate:  req.StartDate,
-		EndDate:    req.EndDate,
+		Title:       req.Title,
+		Description: req.Description,
+		ProjectID:   req.ProjectID,
+		StatusID:    req.StatusID,
+		PriorityID:  req.PriorityID,
+		MilestoneID: req.MilestoneID,
+		SprintID:    req.SprintID,
+		ParentID:    req.ParentID,
+		LabelIDs:    req.LabelIDs,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+
+		EstimateMinutes: req.EstimateMinutes,
+		CustomFields:    req.CustomFields,
 	}
 
-	task, err := h.service.CreateTask(input)
+	task, err := h.service.CreateTask(input, orgID, user.ID)
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to create task")
+		sendTaskError(c, err, "Failed to create task")
 		return
 	}
 
@@ -89,14 +176,30 @@ func (h *Handler) CreateTask(c *gin.Context) {
 // PATCH /tasks/:id
 func (h *Handler) UpdateTask(c *gin.Context) {
 	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
 
 	var req struct {
 		Title       *string    `json:"title"`
+		Description *string    `json:"description"`
 		StatusID    *uint      `json:"status_id"`
 		PriorityID  *uint      `json:"priority_id"`
+		MilestoneID *uint      `json:"milestone_id"`
+		SprintID    *uint      `json:"sprint_id"`
+		ParentID    *uint      `json:"parent_id"`
 		AssigneeIDs []uint     `json:"assignee_ids"`
+		LabelIDs    []uint     `json:"label_ids"`
 		StartDate   *time.Time `json:"start_date"`
 		EndDate     *time.Time `json:"end_date"`
+
+		EstimateMinutes  *int `json:"estimate_minutes"`
+		RemainingMinutes *int `json:"remaining_minutes"`
+
+		CustomFields   map[string]interface{} `json:"custom_fields"`
+		IgnoreBlockers bool                   `json:"ignore_blockers"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -106,16 +209,27 @@ func (h *Handler) UpdateTask(c *gin.Context) {
 
 	input := UpdateTaskInput{
 		Title:       req.Title,
+		Description: req.Description,
 		StatusID:    req.StatusID,
 		PriorityID:  req.PriorityID,
+		MilestoneID: req.MilestoneID,
+		SprintID:    req.SprintID,
+		ParentID:    req.ParentID,
 		AssigneeIDs: req.AssigneeIDs,
+		LabelIDs:    req.LabelIDs,
 		StartDate:   req.StartDate,
 		EndDate:     req.EndDate,
+
+		EstimateMinutes:  req.EstimateMinutes,
+		RemainingMinutes: req.RemainingMinutes,
+
+		CustomFields:   req.CustomFields,
+		IgnoreBlockers: req.IgnoreBlockers,
 	}
 
-	task, err := h.service.UpdateTask(id, input)
+	task, err := h.service.UpdateTask(id, orgID, user.ID, input)
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, err.Error())
+		sendTaskError(c, err, err.Error())
 		return
 	}
 
@@ -125,21 +239,86 @@ func (h *Handler) UpdateTask(c *gin.Context) {
 // DELETE /tasks/:id
 func (h *Handler) DeleteTask(c *gin.Context) {
 	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteTask(id, orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to delete task")
+		return
+	}
+
+	utils.SendSuccess(c, "Task moved to trash")
+}
+
+// POST /tasks/:id/restore
+func (h *Handler) RestoreTask(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
 
-	if err := h.service.DeleteTask(id); err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to delete task")
+	task, err := h.service.RestoreTask(id, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to restore task")
 		return
 	}
 
-	utils.SendSuccess(c, "Task deleted successfully")
+	utils.SendSuccess(c, "Task restored successfully", task)
 }
 
+// GET /tasks/:id/transitions
+func (h *Handler) GetTransitions(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	transitions, err := h.service.GetTransitions(ref, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch task history")
+		return
+	}
+
+	utils.SendSuccess(c, "success", transitions)
+}
+
+// GET /tasks/:id/subtasks
+func (h *Handler) GetSubtasks(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	subtasks, err := h.service.GetSubtasks(ref, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch subtasks")
+		return
+	}
+
+	utils.SendSuccess(c, "success", subtasks)
+}
+
+// GET /projects/:id/status
 func (h *Handler) FindStatusesByProject(c *gin.Context) {
 	projectID := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
 
-	statuses, err := h.service.GetStatuses(projectID)
+	statuses, err := h.service.GetStatuses(projectID, orgID, user.ID)
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch statuses")
+		sendTaskError(c, err, "Failed to fetch statuses")
 		return
 	}
 
@@ -150,10 +329,16 @@ func (h *Handler) FindStatusesByProject(c *gin.Context) {
 func (h *Handler) CreateStatus(c *gin.Context) {
 	projectIDStr := c.Param("id")
 	projectID, _ := strconv.Atoi(projectIDStr) // Helper convert string -> int
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
 
 	var req struct {
-		Name  string `json:"name" binding:"required"`
-		Index int    `json:"index"`
+		Name     string `json:"name" binding:"required"`
+		Index    int    `json:"index"`
+		Category string `json:"category"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -161,9 +346,9 @@ func (h *Handler) CreateStatus(c *gin.Context) {
 		return
 	}
 
-	status, err := h.service.CreateNewStatus(uint(projectID), req.Name, req.Index)
+	status, err := h.service.CreateNewStatus(uint(projectID), orgID, user.ID, req.Name, req.Category)
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to create status")
+		sendTaskError(c, err, "Failed to create status")
 		return
 	}
 
@@ -173,10 +358,16 @@ func (h *Handler) CreateStatus(c *gin.Context) {
 // PATCH /statuses/:id
 func (h *Handler) UpdateStatus(c *gin.Context) {
 	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
 
 	var req struct {
-		Name  *string `json:"name"`
-		Index *int    `json:"index"`
+		Name     *string `json:"name"`
+		Index    *int    `json:"index"`
+		Category *string `json:"category"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -184,10 +375,10 @@ func (h *Handler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	status, err := h.service.UpdateStatus(id, req.Name, req.Index)
+	status, err := h.service.UpdateStatus(id, orgID, user.ID, req.Name, req.Index, req.Category)
 
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, err.Error())
+		sendTaskError(c, err, err.Error())
 		return
 	}
 
@@ -197,10 +388,54 @@ func (h *Handler) UpdateStatus(c *gin.Context) {
 // DELETE /status/:id
 func (h *Handler) DeleteStatus(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.service.DeleteStatus(id); err != nil {
-		// Cek jika error karena masih dipakai task
-		utils.SendError(c, http.StatusBadRequest, "Failed to delete (status might be in use)")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteStatus(id, orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to delete status")
 		return
 	}
 	utils.SendSuccess(c, "Status deleted successfully")
 }
+
+// Helper: reads the org picked by RequireAuth from X-Organization-ID
+func requireOrgID(c *gin.Context) (string, bool) {
+	orgIDInterface, exists := c.Get("org_id")
+	if !exists {
+		utils.SendError(c, http.StatusBadRequest, "X-Organization-ID header is required")
+		return "", false
+	}
+	return orgIDInterface.(string), true
+}
+
+// Helper: maps the service's sentinel errors to status codes, anything else is a 500
+func sendTaskError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrTaskNotFound), errors.Is(err, ErrProjectNotFound), errors.Is(err, ErrCustomFieldNotFound),
+		errors.Is(err, ErrStatusNotFound), errors.Is(err, ErrDependencyNotFound), errors.Is(err, ErrCommentNotFound),
+		errors.Is(err, ErrChecklistItemNotFound), errors.Is(err, ErrLabelNotFound), errors.Is(err, ErrWatcherNotFound),
+		errors.Is(err, ErrTimeEntryNotFound), errors.Is(err, ErrNoRunningTimer):
+		utils.SendError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrForbidden), errors.Is(err, ErrNotCommentAuthor), errors.Is(err, ErrOrgAdminOnly),
+		errors.Is(err, ErrTimesheetForbidden):
+		utils.SendError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, ErrStatusInUse), errors.Is(err, ErrParentTrashed),
+		errors.Is(err, ErrDependencyExists), errors.Is(err, ErrTaskBlocked), errors.Is(err, ErrLabelExists):
+		utils.SendError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrProjectArchived), errors.Is(err, ErrInvalidAssignees),
+		errors.Is(err, ErrInvalidMilestone), errors.Is(err, ErrInvalidSprint),
+		errors.Is(err, ErrInvalidStatus), errors.Is(err, ErrInvalidPriority), errors.Is(err, ErrInvalidCategory),
+		errors.Is(err, ErrDescriptionTooLong), errors.Is(err, ErrInvalidParent),
+		errors.Is(err, ErrTaskCycle), errors.Is(err, ErrTaskTooDeep),
+		errors.Is(err, ErrInvalidDependency), errors.Is(err, ErrDependencyCycle),
+		errors.Is(err, ErrInvalidCustomField), errors.Is(err, ErrInvalidTaskFilter),
+		errors.Is(err, ErrInvalidComment), errors.Is(err, ErrInvalidChecklist), errors.Is(err, ErrInvalidLabel),
+		errors.Is(err, ErrInvalidWatchers), errors.Is(err, ErrInvalidTimeEntry), errors.Is(err, ErrInvalidEstimate):
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.SendError(c, http.StatusInternalServerError, fallback)
+	}
+}
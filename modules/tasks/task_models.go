@@ -1,11 +1,23 @@
 package tasks
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status categories tell reports which statuses mean "not started", "being worked on" and "finished"
+const (
+	StatusCategoryTodo       = "todo"
+	StatusCategoryInProgress = "in_progress"
+	StatusCategoryDone       = "done"
+)
 
 type Status struct {
 	ID        uint   `gorm:"primaryKey" json:"id"`
 	Name      string `json:"name"`
 	Index     int    `json:"index"`
+	Category  string `json:"category"`
 	ProjectID int    `json:"project_id"`
 }
 
@@ -17,8 +29,15 @@ type Priority struct {
 }
 
 type Task struct {
-	ID    uint   `gorm:"primaryKey" json:"id"`
-	Title string `json:"title"`
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	Number uint   `gorm:"uniqueIndex:idx_tasks_project_number,priority:2,where:number > 0" json:"number"`
+	Key    string `gorm:"-" json:"key"` // e.g. "WEB-42", filled from the project key
+	Title  string `json:"title"`
+
+	Description     string   `gorm:"type:text" json:"description"`                      // Markdown as written
+	DescriptionHTML string   `gorm:"type:text" json:"description_html"`                 // sanitized rendering with mentions and references linked
+	MentionIDs      []uint   `gorm:"type:jsonb;serializer:json" json:"mention_ids"`     // users @mentioned in the description
+	ReferencedKeys  []string `gorm:"type:jsonb;serializer:json" json:"referenced_keys"` // tasks like "WEB-42" referenced in the description
 
 	StatusID uint   `json:"status_id"`
 	Status   Status `gorm:"foreignKey:StatusID" json:"status"`
@@ -26,14 +45,51 @@ type Task struct {
 	PriorityID uint     `json:"priority_id"`
 	Priority   Priority `gorm:"foreignKey:PriorityID" json:"priority"`
 
-	ProjectID   uint       `json:"project_id"`
-	AssigneeIDs []uint     `json:"assignee_ids" gorm:"-"`
-	StartDate   *time.Time `json:"start_date"`
-	EndDate     *time.Time `json:"end_date"`
-	CreatedAt   time.Time  `json:"created_at"`
+	ProjectID    uint              `gorm:"uniqueIndex:idx_tasks_project_number,priority:1" json:"project_id"`
+	MilestoneID  *uint             `gorm:"index" json:"milestone_id"`
+	SprintID     *uint             `gorm:"index" json:"sprint_id"` // nil means the task sits in the backlog
+	ParentID     *uint             `gorm:"index" json:"parent_id"` // nil for top-level tasks, see MaxTaskDepth
+	CustomFields CustomFieldValues `gorm:"type:jsonb;serializer:json" json:"custom_fields"`
+	AssigneeIDs  []uint            `json:"assignee_ids" gorm:"-"`
+	Labels       []Label           `json:"labels" gorm:"-"`
+	StartDate    *time.Time        `json:"start_date"`
+	EndDate      *time.Time        `json:"end_date"`
+	CreatedAt    time.Time         `json:"created_at"`
+	CompletedAt  *time.Time        `gorm:"index" json:"completed_at"` // set when the task enters a "done" status
+
+	EstimateMinutes  *int `json:"estimate_minutes"`            // original estimate, nil when not estimated
+	RemainingMinutes *int `json:"remaining_minutes"`           // lowered as time is logged, see LogTime
+	TimeSpentMinutes int  `gorm:"-" json:"time_spent_minutes"` // sum of the logged time entries
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	Progress  *SubtaskProgress   `gorm:"-" json:"subtask_progress"`   // nil when the task has no subtasks
+	Checklist *ChecklistProgress `gorm:"-" json:"checklist"`          // nil when the task has no checklist
+	Subtasks  []Task             `gorm:"-" json:"subtasks,omitempty"` // only filled in tree mode
 }
 
 type TaskUser struct {
 	TaskID uint `gorm:"primaryKey"`
 	UserID uint `gorm:"primaryKey"`
 }
+
+// TaskStatusTransition is one entry of a task's status history; the entry written
+// when the task is created has no FromStatusID
+type TaskStatusTransition struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TaskID       uint      `gorm:"index" json:"task_id"`
+	FromStatusID *uint     `json:"from_status_id"`
+	ToStatusID   uint      `json:"to_status_id"`
+	ChangedBy    uint      `json:"changed_by"` // 0 when recorded by the system (backfill)
+	ChangedAt    time.Time `gorm:"index" json:"changed_at"`
+}
+
+// InitialTransition is the history entry for a freshly created task
+func InitialTransition(task *Task, changedBy uint) TaskStatusTransition {
+	return TaskStatusTransition{
+		TaskID:     task.ID,
+		ToStatusID: task.StatusID,
+		ChangedBy:  changedBy,
+		ChangedAt:  task.CreatedAt,
+	}
+}
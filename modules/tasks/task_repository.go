@@ -1,24 +1,100 @@
 package tasks
 
 import (
+	"fmt"
+	"gotask-backend/models"
+	"gotask-backend/modules/auth"
+
 	"gorm.io/gorm"
 )
 
 type TaskRepository interface {
-	Create(task *Task) error
+	Create(task *Task, createdBy uint) error
 	FindByID(id string) (*Task, error)
-	FindByProjectID(projectID string, page int, limit int) ([]Task, int64, error)
-	Update(task *Task, updates map[string]interface{}) error
+	FindByKey(orgID string, projectKey string, number uint) (*Task, error)
+	FindByProjectID(projectID string, options TaskListOptions) ([]Task, int64, error)
+	Update(task *Task, changes TaskChanges) error
+	FindTransitions(taskID uint) ([]TaskStatusTransition, error)
 	Delete(task *Task) error
-
-	ClearAssignees(task *Task) error
-	AssignUsers(task *Task, userIDs []uint) error
-
-	CheckProjectAccess(projectID string, orgID string) (bool, error)
+	FindTrashedByID(id string) (*Task, error)
+	Restore(task *Task) error
+
+	FindAncestorIDs(taskID uint) ([]uint, error)
+	SubtreeHeight(taskID uint) (int, error)
+	FindSubtasks(taskID uint) ([]Task, error)
+	FindDescendants(taskIDs []uint) ([]Task, error)
+
+	CreateDependency(dependency *TaskDependency, orgID string) (bool, error)
+	DeleteDependency(taskID uint, otherID uint) (int64, error)
+	FindBlockers(taskID uint, orgID string, userID uint) ([]Task, error)
+	FindBlockedTasks(taskID uint, orgID string, userID uint) ([]Task, error)
+	CountOpenBlockers(taskID uint, orgID string, userID uint) (int64, error)
+	FindDependencyGraph(projectID uint, orgID string, userID uint) (*DependencyGraph, error)
+
+	CreateComment(comment *Comment) error
+	FindCommentByID(id string) (*Comment, error)
+	FindComments(taskID uint, page int, limit int) ([]Comment, int64, error)
+	UpdateComment(comment *Comment, revision *CommentRevision, rendered *renderedMarkdown, body string) error
+	DeleteComment(comment *Comment) error
+	FindCommentRevisions(commentID uint) ([]CommentRevision, error)
+
+	FindChecklist(taskID uint) ([]ChecklistItem, error)
+	FindChecklistItemByID(id string) (*ChecklistItem, error)
+	CreateChecklistItems(taskID uint, items []ChecklistItem) error
+	UpdateChecklistItem(item *ChecklistItem, updates map[string]interface{}) error
+	ToggleChecklistItem(item *ChecklistItem) error
+	DeleteChecklistItem(item *ChecklistItem) error
+	ReorderChecklist(taskID uint, itemIDs []uint) error
+	SetChecklistDone(taskID uint, itemIDs []uint, done bool) error
+	DeleteChecklistItems(taskID uint, doneOnly bool) (int64, error)
+
+	AddWatchers(taskID uint, userIDs []uint) error
+	SetWatcherMuted(taskID uint, userID uint, muted bool) error
+	DeleteWatcher(taskID uint, userID uint) (int64, error)
+	FindWatchers(taskID uint) ([]Watcher, error)
+	FindUnmutedWatcherIDs(taskID uint) ([]uint, error)
+
+	CreateTimeEntry(entry *TimeEntry) error
+	FindTimeEntryByID(id string) (*TimeEntry, error)
+	FindTimeEntries(taskID uint) ([]TimeEntry, error)
+	UpdateTimeEntry(entry *TimeEntry, updates map[string]interface{}, minutesDelta int) error
+	DeleteTimeEntry(entry *TimeEntry) error
+	FindRunningTimer(userID uint) (*TimeEntry, error)
+	StartTimer(entry *TimeEntry, running *TimeEntry) error
+	StopTimer(entry *TimeEntry) error
+
+	CreateLabel(label *Label) error
+	FindLabelByID(id string) (*Label, error)
+	FindLabels(orgID string, projectID *uint) ([]Label, error)
+	LabelNameTaken(orgID uint, projectID *uint, name string, exceptID uint) (bool, error)
+	UpdateLabel(label *Label, updates map[string]interface{}) error
+	DeleteLabel(label *Label) error
+	MergeLabels(source *Label, target *Label) (int64, error)
+	FindUsableLabelIDs(orgID string, projectID uint, labelIDs []uint) ([]uint, error)
+	SetTaskLabels(taskID uint, labelIDs []uint) error
+	IsOrgAdmin(orgID string, userID uint) (bool, error)
+
+	FindProjectRole(projectID string, orgID string, userID uint) (string, error)
+	FilterProjectMembers(projectID uint, userIDs []uint) ([]uint, error)
+	IsProjectArchived(projectID uint) (bool, error)
+	MilestoneBelongsToProject(milestoneID uint, projectID uint) (bool, error)
+	SprintAcceptsTasks(sprintID uint, projectID uint) (bool, error)
+	FindMentionedUsers(orgID string, tokens []string) ([]auth.User, error)
+	FindVisibleTaskKeys(orgID string, userID uint, keys []string) ([]string, error)
+
+	CreateCustomField(field *CustomField) error
+	FindCustomFields(projectID uint) ([]CustomField, error)
+	FindCustomFieldByID(id string) (*CustomField, error)
+	UpdateCustomField(field *CustomField, updates map[string]interface{}) error
+	DeleteCustomField(field *CustomField) error
+	CountTasksWithOption(field *CustomField, option string) (int64, error)
 
 	CreateStatus(status *Status) error
 	GetStatusesByProjectID(projectID string) ([]Status, error)
 	FindStatusByID(id string) (*Status, error)
+	FindFirstStatus(projectID uint) (*Status, error)
+	FindPriorityByID(id uint) (*Priority, error)
+	FindPriorityByName(name string) (*Priority, error)
 	UpdateStatus(status *Status, updates map[string]interface{}) error
 	DeleteStatus(status *Status) error
 	BulkUpdateStatuses(statuses []Status) error
@@ -47,40 +123,131 @@ func (r *repository) fetchAssigneeIDs(task *Task) error {
 	return err
 }
 
-func (r *repository) Create(task *Task) error {
-	return r.db.Create(task).Error
+// Helper internal untuk mengisi Key ("WEB-42") dari key project
+func (r *repository) fillKeys(tasks []Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	projectIDs := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		projectIDs = append(projectIDs, t.ProjectID)
+	}
+
+	var projects []struct {
+		ID  uint
+		Key string
+	}
+	err := r.db.Table("projects").
+		Select("id, key").
+		Where("id IN ?", projectIDs).
+		Scan(&projects).Error
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[uint]string)
+	for _, p := range projects {
+		keys[p.ID] = p.Key
+	}
+	for i := range tasks {
+		tasks[i].Key = formatTaskKey(keys[tasks[i].ProjectID], tasks[i].Number)
+	}
+	return nil
+}
+
+// Helper: "WEB-42", or empty for projects and tasks from before keys and numbers
+func formatTaskKey(projectKey string, number uint) string {
+	if projectKey == "" || number == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s-%d", projectKey, number)
+}
+
+// Helper: assignees, keys and subtask progress of listed tasks
+func (r *repository) fillDetails(tasks []Task) {
+	// Looping query is N+1 problem, but acceptable for MVP microservice separation
+	for i := range tasks {
+		_ = r.fetchAssigneeIDs(&tasks[i])
+	}
+	_ = r.fillKeys(tasks)
+	_ = r.fillProgress(tasks)
+	_ = r.fillChecklistProgress(tasks)
+	_ = r.fillLabels(tasks)
+	_ = r.fillTimeSpent(tasks)
+}
+
+// Create allocates the next per-project number and starts the status history
+// in the same transaction as the insert
+func (r *repository) Create(task *Task, createdBy uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		number, err := models.NextTaskNumber(tx, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		task.Number = number
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		transition := InitialTransition(task, createdBy)
+		return tx.Create(&transition).Error
+	})
 }
 
 func (r *repository) FindByID(id string) (*Task, error) {
+	found := make([]Task, 1)
+	err := r.db.Preload("Status").
+		Preload("Priority").
+		Where("tasks.id = ?", id).
+		First(&found[0]).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.fillDetails(found)
+	return &found[0], nil
+}
+
+func (r *repository) FindByKey(orgID string, projectKey string, number uint) (*Task, error) {
 	var task Task
 	err := r.db.Preload("Status").
 		Preload("Priority").
-		First(&task, id).Error
+		Joins("JOIN projects ON projects.id = tasks.project_id AND projects.deleted_at IS NULL").
+		Where("projects.organization_id = ? AND projects.key = ? AND tasks.number = ?", orgID, projectKey, number).
+		First(&task).Error
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Manual Fetch IDs
-	_ = r.fetchAssigneeIDs(&task)
-	return &task, nil
+	found := []Task{task}
+	r.fillDetails(found)
+	return &found[0], nil
 }
 
-func (r *repository) FindByProjectID(projectID string, page int, limit int) ([]Task, int64, error) {
+func (r *repository) FindByProjectID(projectID string, options TaskListOptions) ([]Task, int64, error) {
 	var tasks []Task
 	var total int64
 
-	offset := (page - 1) * limit
+	offset := (options.Page - 1) * options.Limit
+
+	filtered := func() *gorm.DB {
+		return applyTaskFilters(r.db.Model(&Task{}).Where("tasks.project_id = ?", projectID), options)
+	}
 
-	if err := r.db.Model(&Task{}).Where("project_id = ?", projectID).Count(&total).Error; err != nil {
+	if err := filtered().Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.db.Preload("Status").
-		Preload("Priority").
-		Where("project_id = ?", projectID).
-		Order("created_at desc").
-		Limit(limit).
+	query := filtered().Preload("Status").Preload("Priority")
+	for _, sort := range options.Sort {
+		query = query.Order(taskOrder(sort))
+	}
+	err := query.
+		Order("tasks.created_at desc").
+		Order("tasks.id desc").
+		Limit(options.Limit).
 		Offset(offset).
 		Find(&tasks).Error
 
@@ -88,53 +255,164 @@ func (r *repository) FindByProjectID(projectID string, page int, limit int) ([]T
 		return nil, 0, err
 	}
 
-	// Populate IDs for each task (Looping query is N+1 problem, but acceptable for MVP microservice separation)
-	for i := range tasks {
-		_ = r.fetchAssigneeIDs(&tasks[i])
-	}
+	r.fillDetails(tasks)
 	return tasks, total, nil
 }
 
-func (r *repository) Update(task *Task, updates map[string]interface{}) error {
-	return r.db.Model(task).Updates(updates).Error
+// TaskChanges are the writes of one task update
+type TaskChanges struct {
+	Updates          map[string]interface{}
+	Transition       *TaskStatusTransition // recorded in the history when the status changes
+	ReplaceAssignees bool
+	AssigneeIDs      []uint
+	ReplaceLabels    bool
+	LabelIDs         []uint
+}
+
+// Update applies the column updates, status transition, assignees and labels of a task
+// update in one transaction, so a failing step leaves the task as it was
+func (r *repository) Update(task *Task, changes TaskChanges) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(changes.Updates) > 0 {
+			if err := tx.Model(task).Updates(changes.Updates).Error; err != nil {
+				return err
+			}
+		}
+		if changes.Transition != nil {
+			if err := tx.Create(changes.Transition).Error; err != nil {
+				return err
+			}
+		}
+		if changes.ReplaceAssignees {
+			if err := clearAssignees(tx, task.ID); err != nil {
+				return err
+			}
+			if err := assignUsers(tx, task.ID, changes.AssigneeIDs); err != nil {
+				return err
+			}
+		}
+		if changes.ReplaceLabels {
+			return setTaskLabels(tx, task.ID, changes.LabelIDs)
+		}
+		return nil
+	})
+}
+
+func (r *repository) FindTransitions(taskID uint) ([]TaskStatusTransition, error) {
+	var transitions []TaskStatusTransition
+	err := r.db.Where("task_id = ?", taskID).Order("changed_at asc, id asc").Find(&transitions).Error
+	return transitions, err
 }
 
-func (r *repository) Delete(task *Task) error {
-	return r.db.Delete(task).Error
+func (r *repository) FindTrashedByID(id string) (*Task, error) {
+	var task Task
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
 }
 
-func (r *repository) ClearAssignees(task *Task) error {
+func clearAssignees(tx *gorm.DB, taskID uint) error {
 	// Manual Delete dari tabel penghubung
-	return r.db.Exec("DELETE FROM task_users WHERE task_id = ?", task.ID).Error
+	return tx.Exec("DELETE FROM task_users WHERE task_id = ?", taskID).Error
 }
 
-func (r *repository) AssignUsers(task *Task, userIDs []uint) error {
+func assignUsers(tx *gorm.DB, taskID uint, userIDs []uint) error {
 	// Manual Insert ke tabel penghubung
 	// Kita buat struct temporary atau insert map
 	var records []map[string]interface{}
 	for _, uid := range userIDs {
 		records = append(records, map[string]interface{}{
-			"task_id": task.ID,
+			"task_id": taskID,
 			"user_id": uid,
 		})
 	}
 
 	if len(records) > 0 {
-		return r.db.Table("task_users").Create(&records).Error
+		return tx.Table("task_users").Create(&records).Error
 	}
 	return nil
 }
 
-func (r *repository) CheckProjectAccess(projectID string, orgID string) (bool, error) {
-	var count int64
+// FindProjectRole returns the user's effective role on a project of the org,
+// or an empty string when the project does not exist or is hidden from them
+func (r *repository) FindProjectRole(projectID string, orgID string, userID uint) (string, error) {
+	var project struct {
+		ID         uint
+		Visibility string
+		OrgRole    string
+	}
 	err := r.db.Table("projects").
-		Where("id = ? AND organization_id = ?", projectID, orgID).
-		Count(&count).Error
+		Select("projects.id, projects.visibility, organization_users.role AS org_role").
+		Joins("LEFT JOIN organization_users ON organization_users.organization_id = projects.organization_id AND organization_users.user_id = ?", userID).
+		Where("projects.id = ? AND projects.organization_id = ? AND projects.deleted_at IS NULL", projectID, orgID).
+		Scan(&project).Error
+	if err != nil || project.ID == 0 {
+		return "", err
+	}
 
+	var roles []string
+	err = r.db.Table("project_members").
+		Where("project_id = ? AND user_id = ?", project.ID, userID).
+		Pluck("role", &roles).Error
 	if err != nil {
-		return false, err
+		return "", err
+	}
+
+	memberRole := ""
+	if len(roles) > 0 {
+		memberRole = roles[0]
+	}
+	return models.EffectiveProjectRole(project.Visibility, memberRole, project.OrgRole == models.OrgRoleAdmin), nil
+}
+
+// FilterProjectMembers keeps only the users who belong to the project:
+// explicit members, org admins, plus every org member when the project is org-wide
+func (r *repository) FilterProjectMembers(projectID uint, userIDs []uint) ([]uint, error) {
+	var memberIDs []uint
+	if len(userIDs) == 0 {
+		return memberIDs, nil
 	}
-	return count > 0, nil
+
+	err := r.db.Raw(`
+		SELECT user_id FROM project_members WHERE project_id = ? AND user_id IN ?
+		UNION
+		SELECT organization_users.user_id FROM organization_users
+		JOIN projects ON projects.organization_id = organization_users.organization_id
+		WHERE projects.id = ? AND organization_users.user_id IN ?
+		AND (projects.visibility = ? OR organization_users.role = ?)`,
+		projectID, userIDs,
+		projectID, userIDs, models.ProjectVisibilityOrg, models.OrgRoleAdmin,
+	).Scan(&memberIDs).Error
+	return memberIDs, err
+}
+
+func (r *repository) IsProjectArchived(projectID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("projects").
+		Where("id = ? AND archived_at IS NOT NULL", projectID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) MilestoneBelongsToProject(milestoneID uint, projectID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("milestones").
+		Where("id = ? AND project_id = ?", milestoneID, projectID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SprintAcceptsTasks is true for planned or active sprints of the project
+func (r *repository) SprintAcceptsTasks(sprintID uint, projectID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("sprints").
+		Where("id = ? AND project_id = ? AND state <> ?", sprintID, projectID, "closed").
+		Count(&count).Error
+	return count > 0, err
 }
 
 func (r *repository) CreateStatus(status *Status) error {
@@ -149,20 +427,38 @@ func (r *repository) GetStatusesByProjectID(projectID string) ([]Status, error)
 
 func (r *repository) FindStatusByID(id string) (*Status, error) {
 	var status Status
-	err := r.db.First(&status, id).Error
+	err := r.db.Where("id = ?", id).First(&status).Error
 	return &status, err
 }
 
+func (r *repository) FindFirstStatus(projectID uint) (*Status, error) {
+	var status Status
+	err := r.db.Where("project_id = ?", projectID).Order("index asc").First(&status).Error
+	return &status, err
+}
+
+func (r *repository) FindPriorityByID(id uint) (*Priority, error) {
+	var priority Priority
+	err := r.db.Where("id = ?", id).First(&priority).Error
+	return &priority, err
+}
+
+func (r *repository) FindPriorityByName(name string) (*Priority, error) {
+	var priority Priority
+	err := r.db.Where("name = ?", name).First(&priority).Error
+	return &priority, err
+}
+
 func (r *repository) UpdateStatus(status *Status, updates map[string]interface{}) error {
 	return r.db.Model(status).Updates(updates).Error
 }
 
 func (r *repository) DeleteStatus(status *Status) error {
-	// Validasi opsional: Cek apakah status sedang dipakai oleh Task lain
+	// Validasi opsional: Cek apakah status sedang dipakai oleh Task lain (termasuk yang di trash)
 	var count int64
-	r.db.Model(&Task{}).Where("status_id = ?", status.ID).Count(&count)
+	r.db.Unscoped().Model(&Task{}).Where("status_id = ?", status.ID).Count(&count)
 	if count > 0 {
-		return gorm.ErrForeignKeyViolated // Jangan hapus jika masih ada task
+		return ErrStatusInUse // Jangan hapus jika masih ada task
 	}
 
 	return r.db.Delete(status).Error
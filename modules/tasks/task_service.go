@@ -1,179 +1,696 @@
 package tasks
 
 import (
+	"encoding/json"
 	"errors"
+	"gotask-backend/models"
 	"gotask-backend/modules/auth"
+	"gotask-backend/modules/notifications"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type TaskService interface {
-	CreateTask(input CreateTaskInput) (*Task, error)
-	GetTasksByProject(projectID string, orgID string, page int, limit int) ([]Task, int64, error)
-	UpdateTask(id string, input UpdateTaskInput) (*Task, error)
-	DeleteTask(id string) error
-
-	CreateDefaultStatuses(projectID uint) error
-	GetStatuses(projectID string) ([]Status, error)
-	CreateNewStatus(projectID uint, name string, index int) (*Status, error)
-	UpdateStatus(id string, name *string, index *int) (*Status, error)
-	DeleteStatus(id string) error
+	CreateTask(input CreateTaskInput, orgID string, userID uint) (*Task, error)
+	GetTask(ref string, orgID string, userID uint) (*Task, error)
+	GetTasksByProject(projectID string, orgID string, userID uint, query TaskListQuery) ([]Task, int64, error)
+	UpdateTask(id string, orgID string, userID uint, input UpdateTaskInput) (*Task, error)
+	DeleteTask(id string, orgID string, userID uint) error
+	RestoreTask(id string, orgID string, userID uint) (*Task, error)
+	GetTransitions(ref string, orgID string, userID uint) ([]TaskStatusTransition, error)
+	GetSubtasks(ref string, orgID string, userID uint) ([]Task, error)
+
+	GetDependencies(ref string, orgID string, userID uint) (*TaskDependencies, error)
+	AddDependency(ref string, orgID string, userID uint, input DependencyInput) (*TaskDependencies, error)
+	RemoveDependency(ref string, otherRef string, orgID string, userID uint) error
+	GetDependencyGraph(projectID string, orgID string, userID uint) (*DependencyGraph, error)
+
+	GetComments(ref string, orgID string, userID uint, page int, limit int) ([]Comment, int64, error)
+	CreateComment(ref string, orgID string, userID uint, input CommentInput) (*Comment, error)
+	UpdateComment(id string, orgID string, userID uint, body string) (*Comment, error)
+	DeleteComment(id string, orgID string, userID uint) error
+	GetCommentHistory(id string, orgID string, userID uint) ([]CommentRevision, error)
+
+	GetChecklist(ref string, orgID string, userID uint) ([]ChecklistItem, error)
+	AddChecklistItems(ref string, orgID string, userID uint, inputs []ChecklistItemInput) ([]ChecklistItem, error)
+	UpdateChecklistItem(id string, orgID string, userID uint, input UpdateChecklistItemInput) (*ChecklistItem, error)
+	ToggleChecklistItem(id string, orgID string, userID uint) (*ChecklistItem, error)
+	DeleteChecklistItem(id string, orgID string, userID uint) error
+	ReorderChecklist(ref string, orgID string, userID uint, itemIDs []uint) ([]ChecklistItem, error)
+	SetChecklistDone(ref string, orgID string, userID uint, itemIDs []uint, done bool) ([]ChecklistItem, error)
+	ClearChecklist(ref string, orgID string, userID uint, doneOnly bool) (int64, error)
+
+	GetWatchers(ref string, orgID string, userID uint) ([]Watcher, error)
+	Watch(ref string, orgID string, userID uint) (*WatchStatus, error)
+	Unwatch(ref string, orgID string, userID uint) error
+	MuteTask(ref string, orgID string, userID uint, muted bool) (*WatchStatus, error)
+	AddWatchers(ref string, orgID string, userID uint, userIDs []uint) ([]Watcher, error)
+	RemoveWatcher(ref string, watcherID string, orgID string, userID uint) error
+
+	GetTimeEntries(ref string, orgID string, userID uint) ([]TimeEntry, error)
+	LogTime(ref string, orgID string, userID uint, input TimeEntryInput) (*TimeEntry, error)
+	UpdateTimeEntry(id string, orgID string, userID uint, input UpdateTimeEntryInput) (*TimeEntry, error)
+	DeleteTimeEntry(id string, orgID string, userID uint) error
+	GetRunningTimer(userID uint) (*TimeEntry, error)
+	StartTimer(ref string, orgID string, userID uint, note string) (*TimeEntry, *TimeEntry, error)
+	StopTimer(orgID string, userID uint) (*TimeEntry, error)
+
+	GetLabels(projectID string, orgID string, userID uint) ([]Label, error)
+	CreateLabel(orgID string, userID uint, input LabelInput) (*Label, error)
+	UpdateLabel(id string, orgID string, userID uint, input UpdateLabelInput) (*Label, error)
+	DeleteLabel(id string, orgID string, userID uint) error
+	MergeLabels(id string, targetID string, orgID string, userID uint) (*Label, int64, error)
+	AuthorizeProject(projectID string, orgID string, userID uint, minRole string) error
+
+	GetCustomFields(projectID string, orgID string, userID uint) ([]CustomField, error)
+	CreateCustomField(projectID uint, orgID string, userID uint, input CustomFieldInput) (*CustomField, error)
+	UpdateCustomField(id string, orgID string, userID uint, input UpdateCustomFieldInput) (*CustomField, error)
+	DeleteCustomField(id string, orgID string, userID uint) error
+
+	GetStatuses(projectID string, orgID string, userID uint) ([]Status, error)
+	CreateNewStatus(projectID uint, orgID string, userID uint, name string, category string) (*Status, error)
+	UpdateStatus(id string, orgID string, userID uint, name *string, index *int, category *string) (*Status, error)
+	DeleteStatus(id string, orgID string, userID uint) error
 }
 
+var (
+	ErrTaskNotFound     = errors.New("task not found")
+	ErrProjectNotFound  = errors.New("project not found or access denied")
+	ErrForbidden        = errors.New("you do not have the required project role for this action")
+	ErrProjectArchived  = errors.New("project is archived, tasks cannot be created")
+	ErrInvalidAssignees = errors.New("assignees must be members of the project")
+	ErrInvalidMilestone = errors.New("milestone does not belong to the task's project")
+	ErrInvalidSprint    = errors.New("sprint does not belong to the task's project or is already closed")
+	ErrInvalidStatus    = errors.New("status does not belong to the task's project")
+	ErrInvalidPriority  = errors.New("priority does not exist")
+	ErrStatusNotFound   = errors.New("status not found")
+	ErrStatusInUse      = errors.New("status is still used by tasks, move them first")
+	ErrInvalidCategory  = errors.New("category must be 'todo', 'in_progress' or 'done'")
+)
+
+// IsValidStatusCategory checks a category coming from user input
+func IsValidStatusCategory(category string) bool {
+	return category == StatusCategoryTodo || category == StatusCategoryInProgress || category == StatusCategoryDone
+}
+
+// Matches human-readable task references like "WEB-42"
+var taskKeyPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]{1,9})-([0-9]+)$`)
+
 type taskService struct {
 	repo        TaskRepository
 	authService auth.AuthService
+	notifier    notifications.NotificationService
 }
 
-func NewTaskService(repo TaskRepository, authS auth.AuthService) TaskService {
+func NewTaskService(repo TaskRepository, authS auth.AuthService, notifier notifications.NotificationService) TaskService {
 	return &taskService{
 		repo:        repo,
 		authService: authS,
+		notifier:    notifier,
 	}
 }
 
 type CreateTaskInput struct {
-	Title      string
-	ProjectID  uint
-	StatusID   uint
-	PriorityID uint
-	StartDate  *time.Time
-	EndDate    *time.Time
+	Title        string
+	Description  string
+	ProjectID    uint
+	StatusID     uint
+	PriorityID   uint
+	MilestoneID  *uint
+	SprintID     *uint
+	ParentID     *uint
+	CustomFields map[string]interface{}
+	LabelIDs     []uint
+	StartDate    *time.Time
+	EndDate      *time.Time
+
+	EstimateMinutes *int // the remaining time starts out equal to it
 }
 
 type UpdateTaskInput struct {
-	Title       *string
-	StatusID    *uint
-	PriorityID  *uint
-	AssigneeIDs []uint
-	StartDate   *time.Time
-	EndDate     *time.Time
+	Title        *string
+	Description  *string
+	StatusID     *uint
+	PriorityID   *uint
+	MilestoneID  *uint                  // 0 removes the task from its milestone
+	SprintID     *uint                  // 0 moves the task back to the backlog
+	ParentID     *uint                  // 0 makes the task top-level
+	CustomFields map[string]interface{} // merged into the task's values, null clears a field
+	AssigneeIDs  []uint
+	LabelIDs     []uint // replaces the task's labels, empty removes them all
+	StartDate    *time.Time
+	EndDate      *time.Time
+
+	EstimateMinutes  *int // 0 removes the estimate
+	RemainingMinutes *int
+
+	IgnoreBlockers bool // finish the task even if tasks blocking it are still open
+}
+
+// AuthorizeProject checks the caller's effective role on a project of the org.
+// Projects the caller cannot see are reported as not found.
+func (s *taskService) AuthorizeProject(projectID string, orgID string, userID uint, minRole string) error {
+	role, err := s.repo.FindProjectRole(projectID, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return ErrProjectNotFound
+	}
+	if !models.HasProjectRole(role, minRole) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// Helper: resolves a numeric ID or a "WEB-42" style key into a task
+func (s *taskService) findTaskByRef(ref string, orgID string) (*Task, error) {
+	if m := taskKeyPattern.FindStringSubmatch(ref); m != nil {
+		number, err := strconv.ParseUint(m[2], 10, 64)
+		if err != nil {
+			return nil, ErrTaskNotFound
+		}
+		task, err := s.repo.FindByKey(orgID, strings.ToUpper(m[1]), uint(number))
+		if err != nil {
+			return nil, ErrTaskNotFound
+		}
+		return task, nil
+	}
+
+	if _, err := strconv.ParseUint(ref, 10, 64); err != nil {
+		return nil, ErrTaskNotFound
+	}
+	task, err := s.repo.FindByID(ref)
+	if err != nil {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// Helper: loads a task by ID or key and checks the caller's role on its project.
+// Tasks of other organizations are reported as not found.
+func (s *taskService) authorizeTask(ref string, orgID string, userID uint, minRole string) (*Task, error) {
+	task, err := s.findTaskByRef(ref, orgID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.AuthorizeProject(interfaceToString(task.ProjectID), orgID, userID, minRole)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Helper: loads a status and checks the caller's role on its project
+func (s *taskService) authorizeStatus(id string, orgID string, userID uint, minRole string) (*Status, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, ErrStatusNotFound
+	}
+	status, err := s.repo.FindStatusByID(id)
+	if err != nil {
+		return nil, ErrStatusNotFound
+	}
+	err = s.AuthorizeProject(strconv.Itoa(status.ProjectID), orgID, userID, minRole)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil, ErrStatusNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Helper: a task can only use statuses of its own project
+func (s *taskService) checkStatus(statusID uint, projectID uint) (*Status, error) {
+	status, err := s.repo.FindStatusByID(interfaceToString(statusID))
+	if err != nil || uint(status.ProjectID) != projectID {
+		return nil, ErrInvalidStatus
+	}
+	return status, nil
+}
+
+// Helper: priorities are shared by every organization, they only need to exist
+func (s *taskService) checkPriority(priorityID uint) error {
+	if _, err := s.repo.FindPriorityByID(priorityID); err != nil {
+		return ErrInvalidPriority
+	}
+	return nil
+}
+
+// Helper: a task can only join milestones of its own project
+func (s *taskService) checkMilestone(milestoneID uint, projectID uint) error {
+	ok, err := s.repo.MilestoneBelongsToProject(milestoneID, projectID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMilestone
+	}
+	return nil
+}
+
+// Helper: a task can only join open sprints of its own project
+func (s *taskService) checkSprint(sprintID uint, projectID uint) error {
+	ok, err := s.repo.SprintAcceptsTasks(sprintID, projectID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidSprint
+	}
+	return nil
 }
 
-func (s *taskService) CreateTask(input CreateTaskInput) (*Task, error) {
-	// 1. Set Defaults (Business Logic)
+func (s *taskService) GetTask(ref string, orgID string, userID uint) (*Task, error) {
+	return s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+}
+
+func (s *taskService) CreateTask(input CreateTaskInput, orgID string, userID uint) (*Task, error) {
+	if err := s.AuthorizeProject(interfaceToString(input.ProjectID), orgID, userID, models.ProjectRoleContributor); err != nil {
+		return nil, err
+	}
+
+	archived, err := s.repo.IsProjectArchived(input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if archived {
+		return nil, ErrProjectArchived
+	}
+
+	// New tasks start in the project's first column with a medium priority
+	var status *Status
 	if input.StatusID == 0 {
-		input.StatusID = 1 // Assuming ID 1 is "Todo"
+		status, err = s.repo.FindFirstStatus(input.ProjectID)
+		if err != nil {
+			return nil, ErrInvalidStatus
+		}
+		input.StatusID = status.ID
+	} else {
+		status, err = s.checkStatus(input.StatusID, input.ProjectID)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if input.PriorityID == 0 {
-		input.PriorityID = 2 // Assuming ID 2 is "Medium"
+		priority, err := s.repo.FindPriorityByName("Medium")
+		if err != nil {
+			return nil, ErrInvalidPriority
+		}
+		input.PriorityID = priority.ID
+	} else if err := s.checkPriority(input.PriorityID); err != nil {
+		return nil, err
+	}
+
+	if input.MilestoneID != nil {
+		if err := s.checkMilestone(*input.MilestoneID, input.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+	if input.SprintID != nil {
+		if err := s.checkSprint(*input.SprintID, input.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+	if input.ParentID != nil {
+		if err := s.checkParent(0, *input.ParentID, input.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	customFields, err := s.mergeCustomFields(input.ProjectID, nil, input.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := s.renderDescription(input.ProjectID, orgID, userID, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	labelIDs, err := s.checkLabels(input.LabelIDs, input.ProjectID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if input.EstimateMinutes != nil {
+		if err := checkEstimate(*input.EstimateMinutes); err != nil {
+			return nil, err
+		}
+		if *input.EstimateMinutes == 0 {
+			input.EstimateMinutes = nil
+		}
 	}
 
 	task := Task{
-		Title:      input.Title,
-		ProjectID:  input.ProjectID,
-		StatusID:   input.StatusID,
-		PriorityID: input.PriorityID,
-		StartDate:  input.StartDate,
-		EndDate:    input.EndDate,
+		Title:        input.Title,
+		ProjectID:    input.ProjectID,
+		StatusID:     input.StatusID,
+		PriorityID:   input.PriorityID,
+		MilestoneID:  input.MilestoneID,
+		SprintID:     input.SprintID,
+		ParentID:     input.ParentID,
+		CustomFields: customFields,
+		StartDate:    input.StartDate,
+		EndDate:      input.EndDate,
+
+		EstimateMinutes:  input.EstimateMinutes,
+		RemainingMinutes: input.EstimateMinutes,
+
+		Description:     input.Description,
+		DescriptionHTML: description.HTML,
+		MentionIDs:      description.MentionIDs,
+		ReferencedKeys:  description.ReferencedKeys,
+	}
+	if status.Category == StatusCategoryDone {
+		now := time.Now()
+		task.CompletedAt = &now
 	}
 
-	if err := s.repo.Create(&task); err != nil {
+	if err := s.repo.Create(&task, userID); err != nil {
 		return nil, err
 	}
+	if len(labelIDs) > 0 {
+		if err := s.repo.SetTaskLabels(task.ID, labelIDs); err != nil {
+			return nil, err
+		}
+	}
+	s.subscribe(task.ID, append([]uint{userID}, description.MentionIDs...))
 
 	// Return fully loaded task
 	return s.repo.FindByID(interfaceToString(task.ID))
 }
 
-func (s *taskService) GetTasksByProject(projectID string, orgID string, page int, limit int) ([]Task, int64, error) {
-	// Call Repo to check Security
-	hasAccess, err := s.repo.CheckProjectAccess(projectID, orgID)
-	if err != nil {
+func (s *taskService) GetTasksByProject(projectID string, orgID string, userID uint, query TaskListQuery) ([]Task, int64, error) {
+	// Check Security: org ownership and project visibility
+	if err := s.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer); err != nil {
 		return nil, 0, err
 	}
-	if !hasAccess {
-		return nil, 0, errors.New("project not found or access denied")
+
+	id, err := strconv.ParseUint(projectID, 10, 64)
+	if err != nil {
+		return nil, 0, ErrProjectNotFound
+	}
+	options, err := s.buildTaskListOptions(uint(id), userID, query)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Fetch Tasks (Safe now)
-	return s.repo.FindByProjectID(projectID, page, limit)
+	tasks, total, err := s.repo.FindByProjectID(projectID, *options)
+	if err != nil || !query.Tree {
+		return tasks, total, err
+	}
+	tasks, err = s.buildTaskTree(tasks)
+	return tasks, total, err
 }
 
-func (s *taskService) UpdateTask(id string, input UpdateTaskInput) (*Task, error) {
-	task, err := s.repo.FindByID(id)
+func (s *taskService) UpdateTask(id string, orgID string, userID uint, input UpdateTaskInput) (*Task, error) {
+	task, err := s.authorizeTask(id, orgID, userID, models.ProjectRoleContributor)
 	if err != nil {
-		return nil, errors.New("task not found")
+		return nil, err
+	}
+	var labelIDs []uint
+	if input.LabelIDs != nil {
+		if labelIDs, err = s.checkLabels(input.LabelIDs, task.ProjectID, orgID); err != nil {
+			return nil, err
+		}
+	}
+	// Assignees are resolved before anything is written, so an invalid list changes nothing
+	var assigneeIDs []uint
+	if input.AssigneeIDs != nil {
+		// Cek apakah user-user ini valid dengan nanya ke Auth Service
+		users, err := s.authService.GetUsersByIDs(input.AssigneeIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		// Ambil ID-nya saja untuk disimpan di tabel task_users
+		var validIDs []uint
+		for _, u := range users {
+			validIDs = append(validIDs, u.ID)
+		}
+
+		// Assignees harus anggota project
+		if assigneeIDs, err = s.repo.FilterProjectMembers(task.ProjectID, validIDs); err != nil {
+			return nil, err
+		}
+		if len(assigneeIDs) != len(validIDs) {
+			return nil, ErrInvalidAssignees
+		}
 	}
 
 	updates := make(map[string]interface{})
+	var transition *TaskStatusTransition
+	var subscribers []uint // newly mentioned or assigned users start watching
 	if input.Title != nil {
 		updates["title"] = *input.Title
 	}
-	if input.StatusID != nil {
+	if input.Description != nil {
+		rendered, err := s.renderDescription(task.ProjectID, orgID, userID, *input.Description)
+		if err != nil {
+			return nil, err
+		}
+		columns, err := descriptionUpdates(*input.Description, rendered)
+		if err != nil {
+			return nil, err
+		}
+		for column, value := range columns {
+			updates[column] = value
+		}
+		for _, mentionID := range rendered.MentionIDs {
+			if !containsUint(task.MentionIDs, mentionID) {
+				subscribers = append(subscribers, mentionID)
+			}
+		}
+	}
+	if input.StatusID != nil && *input.StatusID != task.StatusID {
 		updates["status_id"] = *input.StatusID
+
+		// Moving into or out of a "done" status starts or clears the completion time
+		status, err := s.checkStatus(*input.StatusID, task.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		wasDone := task.Status.Category == StatusCategoryDone
+		isDone := status.Category == StatusCategoryDone
+		if isDone && !wasDone && !input.IgnoreBlockers {
+			if err := s.checkBlockers(task.ID, orgID, userID); err != nil {
+				return nil, err
+			}
+		}
+		now := time.Now()
+		if isDone && !wasDone {
+			updates["completed_at"] = now
+		} else if !isDone && wasDone {
+			updates["completed_at"] = nil
+		}
+
+		from := task.StatusID
+		transition = &TaskStatusTransition{
+			TaskID:       task.ID,
+			FromStatusID: &from,
+			ToStatusID:   *input.StatusID,
+			ChangedBy:    userID,
+			ChangedAt:    now,
+		}
 	}
 	if input.PriorityID != nil {
+		if err := s.checkPriority(*input.PriorityID); err != nil {
+			return nil, err
+		}
 		updates["priority_id"] = *input.PriorityID
 	}
+	if input.MilestoneID != nil {
+		if *input.MilestoneID == 0 {
+			updates["milestone_id"] = nil
+		} else {
+			if err := s.checkMilestone(*input.MilestoneID, task.ProjectID); err != nil {
+				return nil, err
+			}
+			updates["milestone_id"] = *input.MilestoneID
+		}
+	}
+	if input.SprintID != nil {
+		if *input.SprintID == 0 {
+			updates["sprint_id"] = nil
+		} else {
+			if err := s.checkSprint(*input.SprintID, task.ProjectID); err != nil {
+				return nil, err
+			}
+			updates["sprint_id"] = *input.SprintID
+		}
+	}
+	if input.ParentID != nil {
+		if *input.ParentID == 0 {
+			updates["parent_id"] = nil
+		} else {
+			if err := s.checkParent(task.ID, *input.ParentID, task.ProjectID); err != nil {
+				return nil, err
+			}
+			updates["parent_id"] = *input.ParentID
+		}
+	}
+	if input.CustomFields != nil {
+		customFields, err := s.mergeCustomFields(task.ProjectID, task.CustomFields, input.CustomFields, false)
+		if err != nil {
+			return nil, err
+		}
+		// Map updates skip the serializer tag, so the column gets the JSON itself
+		encoded, err := json.Marshal(customFields)
+		if err != nil {
+			return nil, err
+		}
+		updates["custom_fields"] = string(encoded)
+	}
 	if input.StartDate != nil {
 		updates["start_date"] = *input.StartDate
 	}
 	if input.EndDate != nil {
 		updates["end_date"] = *input.EndDate
 	}
-
-	if len(updates) > 0 {
-		if err := s.repo.Update(task, updates); err != nil {
+	if input.EstimateMinutes != nil {
+		if err := checkEstimate(*input.EstimateMinutes); err != nil {
 			return nil, err
 		}
+		if *input.EstimateMinutes == 0 {
+			updates["estimate_minutes"] = nil
+		} else {
+			updates["estimate_minutes"] = *input.EstimateMinutes
+			// A first estimate is also what remains
+			if task.RemainingMinutes == nil && input.RemainingMinutes == nil {
+				updates["remaining_minutes"] = *input.EstimateMinutes
+			}
+		}
 	}
-
-	// Handle Assignees Sync
-	if input.AssigneeIDs != nil {
-		// Cek apakah user-user ini valid dengan nanya ke Auth Service
-		users, err := s.authService.GetUsersByIDs(input.AssigneeIDs)
-		if err != nil {
+	if input.RemainingMinutes != nil {
+		if err := checkEstimate(*input.RemainingMinutes); err != nil {
 			return nil, err
 		}
+		updates["remaining_minutes"] = *input.RemainingMinutes
+	}
 
-		// Ambil ID-nya saja untuk disimpan di tabel task_users
-		var validIDs []uint
-		for _, u := range users {
-			validIDs = append(validIDs, u.ID)
-		}
+	changes := TaskChanges{
+		Updates:          updates,
+		Transition:       transition,
+		ReplaceAssignees: input.AssigneeIDs != nil,
+		AssigneeIDs:      assigneeIDs,
+		ReplaceLabels:    input.LabelIDs != nil,
+		LabelIDs:         labelIDs,
+	}
+	if err := s.repo.Update(task, changes); err != nil {
+		return nil, err
+	}
 
-		s.repo.ClearAssignees(task)
-		s.repo.AssignUsers(task, validIDs)
+	// Watchers are only subscribed once the update is saved
+	for _, assigneeID := range assigneeIDs {
+		if !containsUint(task.AssigneeIDs, assigneeID) {
+			subscribers = append(subscribers, assigneeID)
+		}
 	}
+	s.subscribe(task.ID, subscribers)
 
-	return s.repo.FindByID(id)
+	return s.repo.FindByID(interfaceToString(task.ID))
 }
 
-func (s *taskService) DeleteTask(id string) error {
-	task, err := s.repo.FindByID(id)
+func (s *taskService) DeleteTask(id string, orgID string, userID uint) error {
+	task, err := s.authorizeTask(id, orgID, userID, models.ProjectRoleContributor)
 	if err != nil {
-		return errors.New("task not found")
+		return err
 	}
 	return s.repo.Delete(task)
 }
 
+func (s *taskService) RestoreTask(id string, orgID string, userID uint) (*Task, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, ErrTaskNotFound
+	}
+	task, err := s.repo.FindTrashedByID(id)
+	if err != nil {
+		return nil, ErrTaskNotFound
+	}
+	// Tasks of a trashed project come back with the project, not on their own
+	err = s.AuthorizeProject(interfaceToString(task.ProjectID), orgID, userID, models.ProjectRoleContributor)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Subtasks come back under their parent, not without it
+	if task.ParentID != nil {
+		if _, err := s.repo.FindTrashedByID(interfaceToString(*task.ParentID)); err == nil {
+			return nil, ErrParentTrashed
+		}
+	}
+
+	if err := s.repo.Restore(task); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(id)
+}
+
 // Helper function to convert uint ID to string
 func interfaceToString(id uint) string {
 	return strconv.FormatUint(uint64(id), 10)
 }
 
-func (s *taskService) CreateDefaultStatuses(projectID uint) error {
-	defaults := []string{"Todo", "On Progress", "Done", "Pending", "Cancel"}
+// GetTransitions returns the status history of a task, oldest first
+func (s *taskService) GetTransitions(ref string, orgID string, userID uint) ([]TaskStatusTransition, error) {
+	task, err := s.GetTask(ref, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindTransitions(task.ID)
+}
 
-	for i, name := range defaults {
-		status := Status{
-			Name:      name,
+// DefaultStatuses are the statuses of a project created without a template
+func DefaultStatuses(projectID uint) []Status {
+	defaults := []struct {
+		Name     string
+		Category string
+	}{
+		{"Todo", StatusCategoryTodo},
+		{"On Progress", StatusCategoryInProgress},
+		{"Done", StatusCategoryDone},
+		{"Pending", StatusCategoryTodo},
+		{"Cancel", StatusCategoryDone},
+	}
+
+	statuses := make([]Status, 0, len(defaults))
+	for i, d := range defaults {
+		statuses = append(statuses, Status{
+			Name:      d.Name,
 			Index:     i,
+			Category:  d.Category,
 			ProjectID: int(projectID),
-		}
-		if err := s.repo.CreateStatus(&status); err != nil {
-			return err
-		}
+		})
 	}
-	return nil
+	return statuses
 }
 
-func (s *taskService) GetStatuses(projectID string) ([]Status, error) {
+func (s *taskService) GetStatuses(projectID string, orgID string, userID uint) ([]Status, error) {
+	if err := s.AuthorizeProject(projectID, orgID, userID, models.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
 	return s.repo.GetStatusesByProjectID(projectID)
 }
 
-func (s *taskService) CreateNewStatus(projectID uint, name string, index int) (*Status, error) {
+func (s *taskService) CreateNewStatus(projectID uint, orgID string, userID uint, name string, category string) (*Status, error) {
+	if err := s.AuthorizeProject(interfaceToString(projectID), orgID, userID, models.ProjectRoleLead); err != nil {
+		return nil, err
+	}
+
+	if category == "" {
+		category = StatusCategoryTodo
+	}
+	if !IsValidStatusCategory(category) {
+		return nil, ErrInvalidCategory
+	}
+
 	getMaxIndex, err := s.repo.GetMaxIndex(strconv.Itoa(int(projectID)))
 	if err != nil {
 		return nil, err
@@ -182,6 +699,7 @@ func (s *taskService) CreateNewStatus(projectID uint, name string, index int) (*
 	status := Status{
 		Name:      name,
 		Index:     getMaxIndex + 1,
+		Category:  category,
 		ProjectID: int(projectID),
 	}
 
@@ -191,10 +709,20 @@ func (s *taskService) CreateNewStatus(projectID uint, name string, index int) (*
 	return &status, nil
 }
 
-func (s *taskService) UpdateStatus(id string, name *string, newIndexPtr *int) (*Status, error) {
-	targetStatus, err := s.repo.FindStatusByID(id)
+func (s *taskService) UpdateStatus(id string, orgID string, userID uint, name *string, newIndexPtr *int, category *string) (*Status, error) {
+	targetStatus, err := s.authorizeStatus(id, orgID, userID, models.ProjectRoleLead)
 	if err != nil {
-		return nil, errors.New("status not found")
+		return nil, err
+	}
+
+	if category != nil {
+		if !IsValidStatusCategory(*category) {
+			return nil, ErrInvalidCategory
+		}
+		if err := s.repo.UpdateStatus(targetStatus, map[string]interface{}{"category": *category}); err != nil {
+			return nil, err
+		}
+		targetStatus.Category = *category
 	}
 
 	if newIndexPtr != nil {
@@ -256,10 +784,10 @@ func (s *taskService) UpdateStatus(id string, name *string, newIndexPtr *int) (*
 	return targetStatus, nil
 }
 
-func (s *taskService) DeleteStatus(id string) error {
-	targetStatus, err := s.repo.FindStatusByID(id)
+func (s *taskService) DeleteStatus(id string, orgID string, userID uint) error {
+	targetStatus, err := s.authorizeStatus(id, orgID, userID, models.ProjectRoleLead)
 	if err != nil {
-		return errors.New("status not found")
+		return err
 	}
 
 	projectStatuses, err := s.repo.GetStatusesByProjectID(strconv.Itoa(targetStatus.ProjectID))
@@ -267,15 +795,20 @@ func (s *taskService) DeleteStatus(id string) error {
 		return errors.New("project statuses not found")
 	}
 
+	if err := s.repo.DeleteStatus(targetStatus); err != nil {
+		return err
+	}
+
 	// Adjust Indexes of Remaining Statuses
+	var remaining []Status
 	for i := range projectStatuses {
+		if projectStatuses[i].ID == targetStatus.ID {
+			continue
+		}
 		if projectStatuses[i].Index > targetStatus.Index {
 			projectStatuses[i].Index -= 1
 		}
+		remaining = append(remaining, projectStatuses[i])
 	}
-	if err := s.repo.BulkUpdateStatuses(projectStatuses); err != nil {
-		return err
-	}
-
-	return s.repo.DeleteStatus(targetStatus)
+	return s.repo.BulkUpdateStatuses(remaining)
 }
@@ -0,0 +1,183 @@
+package tasks
+
+import (
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Helper: time entries are logged per day, given like "2024-06-03"
+func parseEntryDate(c *gin.Context, value *string) (*time.Time, bool) {
+	if value == nil {
+		return nil, true
+	}
+	date, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "date must be a date like 2024-06-03")
+		return nil, false
+	}
+	return &date, true
+}
+
+// GET /tasks/:id/time-entries
+func (h *Handler) GetTimeEntries(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	entries, err := h.service.GetTimeEntries(ref, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch time entries")
+		return
+	}
+
+	utils.SendSuccess(c, "success", entries)
+}
+
+// POST /tasks/:id/time-entries {"minutes": 90, "date": "2024-06-03", "note": "Review with client"}
+func (h *Handler) LogTime(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Minutes int     `json:"minutes" binding:"required"`
+		Date    *string `json:"date"`
+		Note    string  `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	date, ok := parseEntryDate(c, req.Date)
+	if !ok {
+		return
+	}
+
+	entry, err := h.service.LogTime(ref, orgID, user.ID, TimeEntryInput{Minutes: req.Minutes, Date: date, Note: req.Note})
+	if err != nil {
+		sendTaskError(c, err, "Failed to log time")
+		return
+	}
+
+	utils.SendSuccess(c, "Time logged", entry)
+}
+
+// PATCH /time-entries/:id {"minutes": 60, "date": "2024-06-03", "note": "..."}
+func (h *Handler) UpdateTimeEntry(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Minutes *int    `json:"minutes"`
+		Date    *string `json:"date"`
+		Note    *string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	date, ok := parseEntryDate(c, req.Date)
+	if !ok {
+		return
+	}
+
+	entry, err := h.service.UpdateTimeEntry(id, orgID, user.ID, UpdateTimeEntryInput{Minutes: req.Minutes, Date: date, Note: req.Note})
+	if err != nil {
+		sendTaskError(c, err, "Failed to update time entry")
+		return
+	}
+
+	utils.SendSuccess(c, "Time entry updated", entry)
+}
+
+// DELETE /time-entries/:id
+func (h *Handler) DeleteTimeEntry(c *gin.Context) {
+	id := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.DeleteTimeEntry(id, orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to delete time entry")
+		return
+	}
+
+	utils.SendSuccess(c, "Time entry deleted")
+}
+
+// GET /timer (the caller's running timer, null when none is running)
+func (h *Handler) GetTimer(c *gin.Context) {
+	user := c.MustGet("user").(auth.User)
+
+	entry, err := h.service.GetRunningTimer(user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch timer")
+		return
+	}
+
+	utils.SendSuccess(c, "success", entry)
+}
+
+// POST /tasks/:id/timer {"note": "Pairing"} (stops the caller's previous timer, if any)
+func (h *Handler) StartTimer(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	entry, stopped, err := h.service.StartTimer(ref, orgID, user.ID, req.Note)
+	if err != nil {
+		sendTaskError(c, err, "Failed to start timer")
+		return
+	}
+
+	utils.SendSuccess(c, "Timer started", gin.H{
+		"timer":   entry,
+		"stopped": stopped,
+	})
+}
+
+// POST /timer/stop
+func (h *Handler) StopTimer(c *gin.Context) {
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	entry, err := h.service.StopTimer(orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to stop timer")
+		return
+	}
+
+	utils.SendSuccess(c, "Timer stopped", entry)
+}
@@ -0,0 +1,33 @@
+package tasks
+
+import "time"
+
+// Limits of time tracking input; estimates and entries are whole minutes
+const (
+	MaxEstimateMinutes     = 10000 * 60
+	MaxTimeEntryMinutes    = 24 * 60
+	MaxTimeEntryNoteLength = 1000
+)
+
+// TimeEntry is time a user logged on a task. An entry with StartedAt but no
+// StoppedAt is a running timer: it has no minutes yet and counts towards no total.
+// A user runs at most one timer at a time.
+type TimeEntry struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TaskID    uint       `gorm:"index" json:"task_id"`
+	UserID    uint       `gorm:"index;uniqueIndex:idx_time_entries_running,where:started_at IS NOT NULL AND stopped_at IS NULL" json:"user_id"`
+	Date      time.Time  `gorm:"type:date;index" json:"date"` // the day the work was done
+	Minutes   int        `json:"minutes"`
+	Note      string     `json:"note"`
+	StartedAt *time.Time `json:"started_at"` // only set for entries recorded with the timer
+	StoppedAt *time.Time `json:"stopped_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Running reports whether the entry is a timer that has not been stopped
+func (e *TimeEntry) Running() bool {
+	return e.StartedAt != nil && e.StoppedAt == nil
+}
+
+// Condition matching entries that count towards totals, i.e. everything but running timers
+const loggedTimeEntries = "(time_entries.started_at IS NULL OR time_entries.stopped_at IS NOT NULL)"
@@ -0,0 +1,144 @@
+package tasks
+
+import "gorm.io/gorm"
+
+// CreateTimeEntry logs time on a task and takes it off the task's remaining estimate
+func (r *repository) CreateTimeEntry(entry *TimeEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return lowerRemaining(tx, entry.TaskID, entry.Minutes)
+	})
+}
+
+func (r *repository) FindTimeEntryByID(id string) (*TimeEntry, error) {
+	var entry TimeEntry
+	err := r.db.Where("id = ?", id).First(&entry).Error
+	return &entry, err
+}
+
+// FindTimeEntries lists the time logged on a task, latest work first
+func (r *repository) FindTimeEntries(taskID uint) ([]TimeEntry, error) {
+	var entries []TimeEntry
+	err := r.db.Where("task_id = ?", taskID).Order("date desc, created_at desc, id desc").Find(&entries).Error
+	return entries, err
+}
+
+// UpdateTimeEntry corrects an entry; the change in minutes is applied to the task's
+// remaining estimate in the same transaction
+func (r *repository) UpdateTimeEntry(entry *TimeEntry, updates map[string]interface{}, minutesDelta int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(entry).Updates(updates).Error; err != nil {
+			return err
+		}
+		return lowerRemaining(tx, entry.TaskID, minutesDelta)
+	})
+}
+
+// DeleteTimeEntry removes an entry and gives its minutes back to the remaining estimate
+func (r *repository) DeleteTimeEntry(entry *TimeEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(entry).Error; err != nil {
+			return err
+		}
+		return lowerRemaining(tx, entry.TaskID, -entry.Minutes)
+	})
+}
+
+// FindRunningTimer returns nil without an error when the user has no timer running
+func (r *repository) FindRunningTimer(userID uint) (*TimeEntry, error) {
+	var entry TimeEntry
+	err := r.db.Where("user_id = ? AND started_at IS NOT NULL AND stopped_at IS NULL", userID).First(&entry).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// StartTimer creates a running entry, stopping the user's previous timer in the same
+// transaction when there is one (running is the previous timer with its final minutes)
+func (r *repository) StartTimer(entry *TimeEntry, running *TimeEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if running != nil {
+			if err := stopTimer(tx, running); err != nil {
+				return err
+			}
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// StopTimer saves the stop time and minutes of a timer and logs them on its task
+func (r *repository) StopTimer(entry *TimeEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return stopTimer(tx, entry)
+	})
+}
+
+// Helper: only a timer that is still running is stopped, so a concurrent stop cannot log
+// the same time twice
+func stopTimer(tx *gorm.DB, entry *TimeEntry) error {
+	result := tx.Model(&TimeEntry{}).
+		Where("id = ? AND stopped_at IS NULL", entry.ID).
+		Updates(map[string]interface{}{
+			"stopped_at": entry.StoppedAt,
+			"minutes":    entry.Minutes,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoRunningTimer
+	}
+	return lowerRemaining(tx, entry.TaskID, entry.Minutes)
+}
+
+// Helper: lowers the remaining estimate by the minutes (raises it when negative);
+// remaining estimates never drop below zero and unestimated tasks stay so
+func lowerRemaining(tx *gorm.DB, taskID uint, minutes int) error {
+	if minutes == 0 {
+		return nil
+	}
+	return tx.Model(&Task{}).
+		Where("id = ? AND remaining_minutes IS NOT NULL", taskID).
+		Update("remaining_minutes", gorm.Expr("GREATEST(remaining_minutes - ?, 0)", minutes)).Error
+}
+
+// Helper: logged minutes of listed tasks
+func (r *repository) fillTimeSpent(tasks []Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	var rows []struct {
+		TaskID  uint
+		Minutes int
+	}
+	err := r.db.Model(&TimeEntry{}).
+		Select("task_id, SUM(minutes) AS minutes").
+		Where("task_id IN ?", ids).
+		Where(loggedTimeEntries).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	spent := make(map[uint]int)
+	for _, row := range rows {
+		spent[row.TaskID] = row.Minutes
+	}
+	for i := range tasks {
+		tasks[i].TimeSpentMinutes = spent[tasks[i].ID]
+	}
+	return nil
+}
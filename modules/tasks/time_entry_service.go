@@ -0,0 +1,250 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"gotask-backend/models"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	ErrInvalidTimeEntry  = errors.New("invalid time entry")
+	ErrNoRunningTimer    = errors.New("no timer is running")
+	ErrInvalidEstimate   = fmt.Errorf("estimates must be between 0 and %d minutes", MaxEstimateMinutes)
+)
+
+// TimeEntryInput is time logged by hand; Date defaults to today
+type TimeEntryInput struct {
+	Minutes int
+	Date    *time.Time
+	Note    string
+}
+
+type UpdateTimeEntryInput struct {
+	Minutes *int
+	Date    *time.Time
+	Note    *string
+}
+
+// Helper: wraps ErrInvalidTimeEntry with the reason
+func invalidTimeEntry(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidTimeEntry, fmt.Sprintf(format, args...))
+}
+
+// Helper: the calendar day (UTC) a moment falls on, as stored in TimeEntry.Date
+func entryDate(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Helper: entries are whole minutes up to a day, on a day that has started somewhere
+func checkTimeEntry(minutes int, date time.Time, note string) error {
+	if minutes < 1 || minutes > MaxTimeEntryMinutes {
+		return invalidTimeEntry("minutes must be between 1 and %d", MaxTimeEntryMinutes)
+	}
+	if date.After(entryDate(time.Now()).AddDate(0, 0, 1)) {
+		return invalidTimeEntry("date cannot be in the future")
+	}
+	if utf8.RuneCountInString(note) > MaxTimeEntryNoteLength {
+		return invalidTimeEntry("note must be at most %d characters", MaxTimeEntryNoteLength)
+	}
+	return nil
+}
+
+// Helper: estimates are optional whole minutes
+func checkEstimate(minutes int) error {
+	if minutes < 0 || minutes > MaxEstimateMinutes {
+		return ErrInvalidEstimate
+	}
+	return nil
+}
+
+// Helper: loads a time entry and checks the caller's role on the project of its task.
+// Entries on tasks the caller cannot see are reported as not found.
+func (s *taskService) authorizeTimeEntry(id string, orgID string, userID uint, minRole string) (*TimeEntry, *Task, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, nil, ErrTimeEntryNotFound
+	}
+	entry, err := s.repo.FindTimeEntryByID(id)
+	if err != nil {
+		return nil, nil, ErrTimeEntryNotFound
+	}
+	task, err := s.authorizeTask(interfaceToString(entry.TaskID), orgID, userID, minRole)
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, nil, ErrTimeEntryNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	// Other people's time is corrected by project leads only
+	if entry.UserID != userID {
+		if err := s.AuthorizeProject(interfaceToString(task.ProjectID), orgID, userID, models.ProjectRoleLead); err != nil {
+			return nil, nil, err
+		}
+	}
+	return entry, task, nil
+}
+
+// GetTimeEntries lists the time logged on a task, running timers included
+func (s *taskService) GetTimeEntries(ref string, orgID string, userID uint) ([]TimeEntry, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.FindTimeEntries(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []TimeEntry{}
+	}
+	return entries, nil
+}
+
+// LogTime records work on a task by hand; like a stopped timer it lowers the
+// task's remaining estimate by the logged minutes
+func (s *taskService) LogTime(ref string, orgID string, userID uint, input TimeEntryInput) (*TimeEntry, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+
+	date := entryDate(time.Now())
+	if input.Date != nil {
+		date = entryDate(*input.Date)
+	}
+	if err := checkTimeEntry(input.Minutes, date, input.Note); err != nil {
+		return nil, err
+	}
+
+	entry := TimeEntry{
+		TaskID:  task.ID,
+		UserID:  userID,
+		Date:    date,
+		Minutes: input.Minutes,
+		Note:    input.Note,
+	}
+	if err := s.repo.CreateTimeEntry(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// UpdateTimeEntry corrects a logged entry; the remaining estimate follows the change in minutes
+func (s *taskService) UpdateTimeEntry(id string, orgID string, userID uint, input UpdateTimeEntryInput) (*TimeEntry, error) {
+	entry, _, err := s.authorizeTimeEntry(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Running() {
+		return nil, invalidTimeEntry("stop the timer before editing the entry")
+	}
+
+	minutes, date, note := entry.Minutes, entry.Date, entry.Note
+	if input.Minutes != nil {
+		minutes = *input.Minutes
+	}
+	if input.Date != nil {
+		date = entryDate(*input.Date)
+	}
+	if input.Note != nil {
+		note = *input.Note
+	}
+	if err := checkTimeEntry(minutes, date, note); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"minutes": minutes, "date": date, "note": note}
+	if err := s.repo.UpdateTimeEntry(entry, updates, minutes-entry.Minutes); err != nil {
+		return nil, err
+	}
+	entry.Minutes, entry.Date, entry.Note = minutes, date, note
+	return entry, nil
+}
+
+// DeleteTimeEntry removes a logged entry, giving its minutes back to the remaining
+// estimate, or discards a running timer
+func (s *taskService) DeleteTimeEntry(id string, orgID string, userID uint) error {
+	entry, _, err := s.authorizeTimeEntry(id, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteTimeEntry(entry)
+}
+
+// GetRunningTimer returns the caller's running timer, or nil when none is running
+func (s *taskService) GetRunningTimer(userID uint) (*TimeEntry, error) {
+	return s.repo.FindRunningTimer(userID)
+}
+
+// StartTimer starts timing work on a task. A timer already running for the caller
+// is stopped and logged first, and returned as the second value.
+func (s *taskService) StartTimer(ref string, orgID string, userID uint, note string) (*TimeEntry, *TimeEntry, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, nil, err
+	}
+	if utf8.RuneCountInString(note) > MaxTimeEntryNoteLength {
+		return nil, nil, invalidTimeEntry("note must be at most %d characters", MaxTimeEntryNoteLength)
+	}
+
+	now := time.Now()
+	running, err := s.repo.FindRunningTimer(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if running != nil {
+		finishTimer(running, now)
+	}
+
+	entry := TimeEntry{
+		TaskID:    task.ID,
+		UserID:    userID,
+		Date:      entryDate(now),
+		Note:      note,
+		StartedAt: &now,
+	}
+	if err := s.repo.StartTimer(&entry, running); err != nil {
+		return nil, nil, err
+	}
+	return &entry, running, nil
+}
+
+// StopTimer stops the caller's running timer and logs the time on its task. Like logging
+// time by hand it needs contributor access to the task, in the given organization.
+func (s *taskService) StopTimer(orgID string, userID uint) (*TimeEntry, error) {
+	running, err := s.repo.FindRunningTimer(userID)
+	if err != nil {
+		return nil, err
+	}
+	if running == nil {
+		return nil, ErrNoRunningTimer
+	}
+	if _, err := s.authorizeTask(interfaceToString(running.TaskID), orgID, userID, models.ProjectRoleContributor); err != nil {
+		return nil, err
+	}
+
+	finishTimer(running, time.Now())
+	if err := s.repo.StopTimer(running); err != nil {
+		return nil, err
+	}
+	return running, nil
+}
+
+// Helper: a stopped timer counts started minutes, at least one and at most a day;
+// a timer forgotten overnight is capped and can be corrected afterwards
+func finishTimer(entry *TimeEntry, stoppedAt time.Time) {
+	minutes := int(math.Ceil(stoppedAt.Sub(*entry.StartedAt).Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+	if minutes > MaxTimeEntryMinutes {
+		minutes = MaxTimeEntryMinutes
+	}
+	entry.StoppedAt = &stoppedAt
+	entry.Minutes = minutes
+}
@@ -0,0 +1,136 @@
+package tasks
+
+import (
+	"gotask-backend/modules/auth"
+	"gotask-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /tasks/:id/watchers
+func (h *Handler) GetWatchers(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	watchers, err := h.service.GetWatchers(ref, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to fetch watchers")
+		return
+	}
+
+	utils.SendSuccess(c, "success", watchers)
+}
+
+// POST /tasks/:id/watchers {"user_ids": [4, 7]}
+func (h *Handler) AddWatchers(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	var req struct {
+		UserIDs []uint `json:"user_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	watchers, err := h.service.AddWatchers(ref, orgID, user.ID, req.UserIDs)
+	if err != nil {
+		sendTaskError(c, err, "Failed to add watchers")
+		return
+	}
+
+	utils.SendSuccess(c, "Watchers added", watchers)
+}
+
+// DELETE /tasks/:id/watchers/:userId
+func (h *Handler) RemoveWatcher(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.RemoveWatcher(ref, c.Param("userId"), orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to remove watcher")
+		return
+	}
+
+	utils.SendSuccess(c, "Watcher removed")
+}
+
+// POST /tasks/:id/watch
+func (h *Handler) Watch(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	status, err := h.service.Watch(ref, orgID, user.ID)
+	if err != nil {
+		sendTaskError(c, err, "Failed to watch task")
+		return
+	}
+
+	utils.SendSuccess(c, "Watching task", status)
+}
+
+// DELETE /tasks/:id/watch
+func (h *Handler) Unwatch(c *gin.Context) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	if err := h.service.Unwatch(ref, orgID, user.ID); err != nil {
+		sendTaskError(c, err, "Failed to unwatch task")
+		return
+	}
+
+	utils.SendSuccess(c, "Stopped watching task", WatchStatus{})
+}
+
+// POST /tasks/:id/mute
+func (h *Handler) MuteTask(c *gin.Context) {
+	h.setMuted(c, true)
+}
+
+// DELETE /tasks/:id/mute
+func (h *Handler) UnmuteTask(c *gin.Context) {
+	h.setMuted(c, false)
+}
+
+func (h *Handler) setMuted(c *gin.Context, muted bool) {
+	ref := c.Param("id")
+	orgID, ok := requireOrgID(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(auth.User)
+
+	status, err := h.service.MuteTask(ref, orgID, user.ID, muted)
+	if err != nil {
+		sendTaskError(c, err, "Failed to update task notifications")
+		return
+	}
+
+	message := "Task unmuted"
+	if muted {
+		message = "Task muted"
+	}
+	utils.SendSuccess(c, message, status)
+}
@@ -0,0 +1,27 @@
+package tasks
+
+import "time"
+
+// TaskWatcher subscribes a user to a task. Creators, assignees, commenters and
+// mentioned users are subscribed automatically; a muted watcher stays subscribed,
+// so it is not re-added later, but gets no notifications about the task.
+type TaskWatcher struct {
+	TaskID    uint      `gorm:"primaryKey" json:"task_id"`
+	UserID    uint      `gorm:"primaryKey;index" json:"user_id"`
+	Muted     bool      `json:"muted"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Watcher is a task watcher with the user's email, for listings
+type Watcher struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	Muted     bool      `json:"muted"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WatchStatus is the caller's own subscription to a task
+type WatchStatus struct {
+	Watching bool `json:"watching"`
+	Muted    bool `json:"muted"`
+}
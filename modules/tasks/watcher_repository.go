@@ -0,0 +1,49 @@
+package tasks
+
+import "gorm.io/gorm/clause"
+
+// AddWatchers subscribes the users; existing watchers keep their muted flag
+func (r *repository) AddWatchers(taskID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	watchers := make([]TaskWatcher, 0, len(userIDs))
+	for _, userID := range userIDs {
+		watchers = append(watchers, TaskWatcher{TaskID: taskID, UserID: userID})
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&watchers).Error
+}
+
+// SetWatcherMuted subscribes the user if needed and sets the muted flag
+func (r *repository) SetWatcherMuted(taskID uint, userID uint, muted bool) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"muted"}),
+	}).Create(&TaskWatcher{TaskID: taskID, UserID: userID, Muted: muted}).Error
+}
+
+func (r *repository) DeleteWatcher(taskID uint, userID uint) (int64, error) {
+	result := r.db.Where("task_id = ? AND user_id = ?", taskID, userID).Delete(&TaskWatcher{})
+	return result.RowsAffected, result.Error
+}
+
+// FindWatchers lists the watchers of a task in the order they subscribed
+func (r *repository) FindWatchers(taskID uint) ([]Watcher, error) {
+	var watchers []Watcher
+	err := r.db.Model(&TaskWatcher{}).
+		Select("task_watchers.user_id, users.email, task_watchers.muted, task_watchers.created_at").
+		Joins("JOIN users ON users.id = task_watchers.user_id").
+		Where("task_watchers.task_id = ?", taskID).
+		Order("task_watchers.created_at asc, task_watchers.user_id asc").
+		Scan(&watchers).Error
+	return watchers, err
+}
+
+// FindUnmutedWatcherIDs lists the watchers who still want to hear about the task
+func (r *repository) FindUnmutedWatcherIDs(taskID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&TaskWatcher{}).
+		Where("task_id = ? AND muted = ?", taskID, false).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
@@ -0,0 +1,148 @@
+package tasks
+
+import (
+	"errors"
+	"gotask-backend/models"
+	"log"
+	"strconv"
+)
+
+var (
+	ErrWatcherNotFound = errors.New("user is not watching this task")
+	ErrInvalidWatchers = errors.New("watchers must be members of the project")
+)
+
+// GetWatchers lists everyone subscribed to a task, muted or not
+func (s *taskService) GetWatchers(ref string, orgID string, userID uint) ([]Watcher, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	watchers, err := s.repo.FindWatchers(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if watchers == nil {
+		watchers = []Watcher{}
+	}
+	return watchers, nil
+}
+
+// Watch subscribes the caller to a task they can see; watching again unmutes it
+func (s *taskService) Watch(ref string, orgID string, userID uint) (*WatchStatus, error) {
+	return s.MuteTask(ref, orgID, userID, false)
+}
+
+// Unwatch drops the caller's subscription. Being mentioned or assigned later
+// subscribes them again; muting is the way to stay quiet for good.
+func (s *taskService) Unwatch(ref string, orgID string, userID uint) error {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.DeleteWatcher(task.ID, userID)
+	return err
+}
+
+// MuteTask keeps the caller subscribed but silences (or restores) their notifications
+func (s *taskService) MuteTask(ref string, orgID string, userID uint, muted bool) (*WatchStatus, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetWatcherMuted(task.ID, userID, muted); err != nil {
+		return nil, err
+	}
+	return &WatchStatus{Watching: true, Muted: muted}, nil
+}
+
+// AddWatchers subscribes other project members to a task
+func (s *taskService) AddWatchers(ref string, orgID string, userID uint, userIDs []uint) ([]Watcher, error) {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleContributor)
+	if err != nil {
+		return nil, err
+	}
+	if len(userIDs) == 0 {
+		return nil, ErrInvalidWatchers
+	}
+
+	unique := make([]uint, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !containsUint(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	memberIDs, err := s.repo.FilterProjectMembers(task.ProjectID, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberIDs) != len(unique) {
+		return nil, ErrInvalidWatchers
+	}
+
+	if err := s.repo.AddWatchers(task.ID, memberIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.FindWatchers(task.ID)
+}
+
+// RemoveWatcher unsubscribes someone else from a task, which takes a project lead
+func (s *taskService) RemoveWatcher(ref string, watcherID string, orgID string, userID uint) error {
+	task, err := s.authorizeTask(ref, orgID, userID, models.ProjectRoleViewer)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(watcherID, 10, 64)
+	if err != nil {
+		return ErrWatcherNotFound
+	}
+	if uint(id) != userID {
+		if err := s.AuthorizeProject(interfaceToString(task.ProjectID), orgID, userID, models.ProjectRoleLead); err != nil {
+			return err
+		}
+	}
+
+	removed, err := s.repo.DeleteWatcher(task.ID, uint(id))
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrWatcherNotFound
+	}
+	return nil
+}
+
+// Helper: auto-subscription happens after the change is saved, a failure must not undo it
+func (s *taskService) subscribe(taskID uint, userIDs []uint) {
+	if len(userIDs) == 0 {
+		return
+	}
+	if err := s.repo.AddWatchers(taskID, userIDs); err != nil {
+		log.Printf("subscribe watchers to task %d failed: %v", taskID, err)
+	}
+}
+
+// Helper: the recipient list for any notification about a task. Muted watchers are
+// left out, and so are watchers who lost access to the project since subscribing.
+func (s *taskService) recipients(task *Task) []uint {
+	watcherIDs, err := s.repo.FindUnmutedWatcherIDs(task.ID)
+	if err == nil {
+		watcherIDs, err = s.repo.FilterProjectMembers(task.ProjectID, watcherIDs)
+	}
+	if err != nil {
+		log.Printf("find watchers of task %d failed: %v", task.ID, err)
+		return nil
+	}
+	return watcherIDs
+}
+
+// Helper: keeps the users that are also in recipients
+func onlyRecipients(userIDs []uint, recipients []uint) []uint {
+	var kept []uint
+	for _, id := range userIDs {
+		if containsUint(recipients, id) {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Storage keeps uploaded files as opaque objects addressed by a slash-separated key
+type Storage interface {
+	Put(key string, body io.Reader, size int64, contentType string) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error // deleting a missing object is not an error
+}
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// FromEnv picks the backend from STORAGE_DRIVER:
+//   - "local" (default): files under STORAGE_DIR, default "uploads"
+//   - "s3": bucket S3_BUCKET at S3_ENDPOINT (S3_REGION, S3_ACCESS_KEY, S3_SECRET_KEY)
+func FromEnv() (Storage, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		dir := os.Getenv("STORAGE_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		return NewLocalStorage(dir)
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q, expected 'local' or 's3'", driver)
+	}
+}
+
+// Helper: keys are relative paths without empty, "." or ".." segments
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.Contains(segment, "\\") {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
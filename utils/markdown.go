@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// Kinds of tokens that RenderMarkdown can turn into links
+const (
+	MarkdownMention = "mention"  // "@alice" or "@alice@example.com", the token has no "@" prefix
+	MarkdownTaskRef = "task-ref" // "WEB-42"
+)
+
+// MarkdownLinker returns the link target of a token found in the text;
+// ok=false leaves the token as plain text
+type MarkdownLinker func(kind string, token string) (href string, ok bool)
+
+// No autolinking: "@alice@example.com" must stay a mention, not become a mail link
+var markdown = goldmark.New(goldmark.WithExtensions(extension.Table, extension.Strikethrough))
+
+// Raw HTML is already dropped by goldmark; the policy is the second line of defense
+var markdownPolicy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^(mention|task-ref)$`)).OnElements("a")
+	return p
+}()
+
+var markdownToken = regexp.MustCompile(`@([A-Za-z0-9._%+-]+(?:@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+)?)|[A-Z][A-Z0-9]{1,9}-[0-9]+`)
+
+type markdownMatch struct {
+	start, end int // byte offsets in the source
+	kind       string
+	token      string
+}
+
+// RenderMarkdown converts Markdown to sanitized HTML, linking mentions and task
+// references through linker (which may be nil). Code spans and blocks are left alone.
+func RenderMarkdown(source string, linker MarkdownLinker) string {
+	src := []byte(source)
+	doc := markdown.Parser().Parse(text.NewReader(src))
+
+	for _, t := range markdownTexts(doc, src) {
+		var links []markdownMatch
+		var hrefs []string
+		for _, m := range findMarkdownTokens(t, src) {
+			if linker == nil {
+				break
+			}
+			if href, ok := linker(m.kind, m.token); ok {
+				links = append(links, m)
+				hrefs = append(hrefs, href)
+			}
+		}
+		if len(links) > 0 {
+			splitLinks(t, links, hrefs)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, src, doc); err != nil {
+		return ""
+	}
+	return markdownPolicy.Sanitize(buf.String())
+}
+
+// MarkdownTokens lists the distinct mentions and task references of the text,
+// in order of appearance; code spans and blocks are ignored
+func MarkdownTokens(source string) (mentions []string, refs []string) {
+	src := []byte(source)
+	doc := markdown.Parser().Parse(text.NewReader(src))
+
+	seen := make(map[string]bool)
+	for _, t := range markdownTexts(doc, src) {
+		for _, m := range findMarkdownTokens(t, src) {
+			if seen[m.kind+m.token] {
+				continue
+			}
+			seen[m.kind+m.token] = true
+			if m.kind == MarkdownMention {
+				mentions = append(mentions, m.token)
+			} else {
+				refs = append(refs, m.token)
+			}
+		}
+	}
+	return mentions, refs
+}
+
+// Helper: the text nodes outside code and links. Neighbouring pieces of one run of
+// text (split by characters like "_" that could have been emphasis) are merged first.
+func markdownTexts(doc ast.Node, src []byte) []*ast.Text {
+	var texts []*ast.Text
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindCodeSpan, ast.KindLink, ast.KindAutoLink, ast.KindImage, ast.KindRawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		if t, ok := n.(*ast.Text); ok && !t.IsRaw() {
+			for next, ok := t.NextSibling().(*ast.Text); ok && t.Merge(next, src); next, ok = t.NextSibling().(*ast.Text) {
+				t.Parent().RemoveChild(t.Parent(), next)
+			}
+			texts = append(texts, t)
+		}
+		return ast.WalkContinue, nil
+	})
+	return texts
+}
+
+func findMarkdownTokens(t *ast.Text, src []byte) []markdownMatch {
+	offset := t.Segment.Start
+	value := t.Segment.Value(src)
+
+	var matches []markdownMatch
+	for _, loc := range markdownToken.FindAllSubmatchIndex(value, -1) {
+		start, end := loc[0], loc[1]
+		// Mentions and references must start a word: "bob@example.com" and "X-WEB-1" are not tokens
+		if start > 0 && isTokenChar(value[start-1]) {
+			continue
+		}
+
+		if loc[2] >= 0 {
+			token := strings.TrimRight(string(value[loc[2]:loc[3]]), ".")
+			if token == "" {
+				continue
+			}
+			end = loc[2] + len(token)
+			matches = append(matches, markdownMatch{offset + start, offset + end, MarkdownMention, token})
+		} else {
+			if end < len(value) && isTokenChar(value[end]) {
+				continue
+			}
+			matches = append(matches, markdownMatch{offset + start, offset + end, MarkdownTaskRef, string(value[start:end])})
+		}
+	}
+	return matches
+}
+
+func isTokenChar(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || strings.IndexByte("._%+-@", b) >= 0
+}
+
+// Helper: replaces the matched ranges of a text node with links
+func splitLinks(t *ast.Text, matches []markdownMatch, hrefs []string) {
+	parent := t.Parent()
+	pos := t.Segment.Start
+	for i, m := range matches {
+		if m.start > pos {
+			parent.InsertBefore(parent, t, ast.NewTextSegment(text.NewSegment(pos, m.start)))
+		}
+		link := ast.NewLink()
+		link.Destination = []byte(hrefs[i])
+		link.SetAttributeString("class", []byte(m.kind))
+		link.AppendChild(link, ast.NewTextSegment(text.NewSegment(m.start, m.end)))
+		parent.InsertBefore(parent, t, link)
+		pos = m.end
+	}
+	// The remainder keeps the node's line break flags
+	t.Segment = text.NewSegment(pos, t.Segment.Stop)
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"log"
+	"time"
+)
+
+// Every runs job in the background right away and then once per interval.
+// Errors are logged, the schedule keeps going.
+func Every(interval time.Duration, name string, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := job(); err != nil {
+				log.Printf("job %s failed: %v", name, err)
+			}
+			<-ticker.C
+		}
+	}()
+}